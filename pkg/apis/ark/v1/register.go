@@ -0,0 +1,69 @@
+/*
+Copyright 2017 the Heptio Ark contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupName is the group name used in this package.
+const GroupName = "ark.heptio.com"
+
+const (
+	// BackupNameLabel is the label key used to identify the backup that a
+	// related object (e.g. a DeleteBackupRequest) refers to.
+	BackupNameLabel = "ark.heptio.com/backup-name"
+	// BackupUIDLabel is the label key used to identify the UID of the backup
+	// that a related object (e.g. a DeleteBackupRequest) refers to.
+	BackupUIDLabel = "ark.heptio.com/backup-uid"
+	// ScheduleNameLabel is the label key used on a Backup to identify the Schedule that created it.
+	ScheduleNameLabel = "ark.heptio.com/schedule-name"
+)
+
+// SchemeGroupVersion is the group version used to register these objects.
+var SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1"}
+
+// Resource takes an unqualified resource and returns a Group-qualified GroupResource.
+func Resource(resource string) schema.GroupResource {
+	return SchemeGroupVersion.WithResource(resource).GroupResource()
+}
+
+var (
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+	AddToScheme   = SchemeBuilder.AddToScheme
+)
+
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion,
+		&Backup{},
+		&BackupList{},
+		&Restore{},
+		&RestoreList{},
+		&BackupStorageLocation{},
+		&BackupStorageLocationList{},
+		&Schedule{},
+		&ScheduleList{},
+		&DeleteBackupRequest{},
+		&DeleteBackupRequestList{},
+		&DeleteBackupsRequest{},
+		&DeleteBackupsRequestList{},
+	)
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	return nil
+}
@@ -0,0 +1,397 @@
+/*
+Copyright 2017 the Heptio Ark contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Backup is an Ark resource that represents the capture of Kubernetes
+// cluster state at a point in time.
+type Backup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BackupSpec   `json:"spec,omitempty"`
+	Status BackupStatus `json:"status,omitempty"`
+}
+
+// BackupSpec defines the specification for an Ark backup.
+type BackupSpec struct {
+	IncludedNamespaces []string        `json:"includedNamespaces,omitempty"`
+	ExcludedNamespaces []string        `json:"excludedNamespaces,omitempty"`
+	SnapshotVolumes    *bool           `json:"snapshotVolumes,omitempty"`
+	TTL                metav1.Duration `json:"ttl,omitempty"`
+	StorageLocation    string          `json:"storageLocation,omitempty"`
+}
+
+// BackupPhase is a string representation of the lifecycle phase of an Ark backup.
+type BackupPhase string
+
+const (
+	BackupPhaseNew              BackupPhase = "New"
+	BackupPhaseFailedValidation BackupPhase = "FailedValidation"
+	BackupPhaseInProgress       BackupPhase = "InProgress"
+	BackupPhaseCompleted        BackupPhase = "Completed"
+	BackupPhasePartiallyFailed  BackupPhase = "PartiallyFailed"
+	BackupPhaseFailed           BackupPhase = "Failed"
+	BackupPhaseDeleting         BackupPhase = "Deleting"
+	// BackupPhasePendingDeletion means the backup has been tombstoned by a Soft-mode
+	// DeleteBackupRequest and is waiting out its recovery window.
+	BackupPhasePendingDeletion BackupPhase = "PendingDeletion"
+)
+
+// VolumeBackupInfo records the provider-specific details of a single
+// persistent volume snapshot taken as part of a Backup.
+type VolumeBackupInfo struct {
+	SnapshotID       string `json:"snapshotID"`
+	Type             string `json:"type,omitempty"`
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+}
+
+// BackupStatus captures the current status of an Ark backup.
+type BackupStatus struct {
+	Version          int                          `json:"version,omitempty"`
+	Expiration       metav1.Time                  `json:"expiration,omitempty"`
+	Phase            BackupPhase                  `json:"phase,omitempty"`
+	ValidationErrors []string                     `json:"validationErrors,omitempty"`
+	VolumeBackups    map[string]*VolumeBackupInfo `json:"volumeBackups,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BackupList is a list of Backups.
+type BackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Backup `json:"items"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Restore is an Ark resource that represents the application of
+// resources from an Ark backup to a target Kubernetes cluster.
+type Restore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RestoreSpec   `json:"spec,omitempty"`
+	Status RestoreStatus `json:"status,omitempty"`
+}
+
+// RestoreSpec defines the specification for an Ark restore.
+type RestoreSpec struct {
+	BackupName         string   `json:"backupName"`
+	IncludedNamespaces []string `json:"includedNamespaces,omitempty"`
+	ExcludedNamespaces []string `json:"excludedNamespaces,omitempty"`
+}
+
+// RestorePhase is a string representation of the lifecycle phase of an Ark restore.
+type RestorePhase string
+
+const (
+	RestorePhaseNew              RestorePhase = "New"
+	RestorePhaseFailedValidation RestorePhase = "FailedValidation"
+	RestorePhaseInProgress       RestorePhase = "InProgress"
+	RestorePhaseCompleted        RestorePhase = "Completed"
+	RestorePhaseFailed           RestorePhase = "Failed"
+)
+
+// RestoreStatus captures the current status of an Ark restore.
+type RestoreStatus struct {
+	Phase    RestorePhase `json:"phase,omitempty"`
+	Warnings int          `json:"warnings,omitempty"`
+	Errors   int          `json:"errors,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RestoreList is a list of Restores.
+type RestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Restore `json:"items"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BackupStorageLocation is an Ark resource that defines a storage location for Ark backups.
+type BackupStorageLocation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec BackupStorageLocationSpec `json:"spec,omitempty"`
+}
+
+// BackupStorageLocationSpec defines the specification for an Ark BackupStorageLocation.
+type BackupStorageLocationSpec struct {
+	Provider   string                          `json:"provider,omitempty"`
+	Bucket     string                          `json:"bucket,omitempty"`
+	Config     map[string]string               `json:"config,omitempty"`
+	AccessMode BackupStorageLocationAccessMode `json:"accessMode,omitempty"`
+}
+
+// BackupStorageLocationAccessMode represents the permissions for a BackupStorageLocation.
+type BackupStorageLocationAccessMode string
+
+const (
+	// BackupStorageLocationAccessModeReadWrite allows Ark to read from and write to the location.
+	BackupStorageLocationAccessModeReadWrite BackupStorageLocationAccessMode = "ReadWrite"
+	// BackupStorageLocationAccessModeReadOnly allows Ark to only read from the location; writes
+	// and deletes (including reclaiming an expired backup's storage) are refused.
+	BackupStorageLocationAccessModeReadOnly BackupStorageLocationAccessMode = "ReadOnly"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BackupStorageLocationList is a list of BackupStorageLocations.
+type BackupStorageLocationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BackupStorageLocation `json:"items"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Schedule is an Ark resource that represents a periodic backup schedule.
+type Schedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ScheduleSpec   `json:"spec,omitempty"`
+	Status ScheduleStatus `json:"status,omitempty"`
+}
+
+// ScheduleSpec defines the specification for an Ark schedule.
+type ScheduleSpec struct {
+	Template BackupSpec `json:"template,omitempty"`
+	Schedule string     `json:"schedule"`
+}
+
+// SchedulePhase is a string representation of the lifecycle phase of an Ark schedule.
+type SchedulePhase string
+
+const (
+	SchedulePhaseNew              SchedulePhase = "New"
+	SchedulePhaseEnabled          SchedulePhase = "Enabled"
+	SchedulePhaseFailedValidation SchedulePhase = "FailedValidation"
+)
+
+// ScheduleStatus captures the current status of an Ark schedule.
+type ScheduleStatus struct {
+	Phase SchedulePhase `json:"phase,omitempty"`
+	// LastBackupName is the name of the most recent Backup created by this schedule.
+	LastBackupName string `json:"lastBackupName,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ScheduleList is a list of Schedules.
+type ScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Schedule `json:"items"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DeleteBackupRequest is an Ark resource that represents a request to
+// delete a Backup and its associated backup files, snapshots, and restores.
+type DeleteBackupRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DeleteBackupRequestSpec   `json:"spec,omitempty"`
+	Status DeleteBackupRequestStatus `json:"status,omitempty"`
+}
+
+// DeleteBackupRequestSpec is the specification for which backup to delete.
+type DeleteBackupRequestSpec struct {
+	BackupName string `json:"backupName"`
+
+	// Mode controls whether the backup's storage and snapshots are reclaimed immediately, or
+	// only after a recovery window has elapsed. Defaults to DeleteBackupRequestModeImmediate.
+	// +optional
+	Mode DeleteBackupRequestMode `json:"mode,omitempty"`
+}
+
+// DeleteBackupRequestMode is a string representation of how a DeleteBackupRequest should be
+// carried out.
+type DeleteBackupRequestMode string
+
+const (
+	// DeleteBackupRequestModeImmediate reclaims the backup's storage and snapshots as soon as
+	// the request is processed.
+	DeleteBackupRequestModeImmediate DeleteBackupRequestMode = "Immediate"
+	// DeleteBackupRequestModeSoft tombstones the backup and defers reclaiming its storage and
+	// snapshots until the recovery window configured on the controller has elapsed.
+	DeleteBackupRequestModeSoft DeleteBackupRequestMode = "Soft"
+)
+
+// DeleteBackupRequestPhase is a string representation of the lifecycle
+// phase of an Ark DeleteBackupRequest.
+type DeleteBackupRequestPhase string
+
+const (
+	// DeleteBackupRequestPhaseNew means the request has not been processed yet.
+	DeleteBackupRequestPhaseNew DeleteBackupRequestPhase = "New"
+	// DeleteBackupRequestPhaseInProgress means the request is being processed.
+	DeleteBackupRequestPhaseInProgress DeleteBackupRequestPhase = "InProgress"
+	// DeleteBackupRequestPhasePendingDeletion means the backup has been tombstoned and is
+	// waiting out its recovery window before its storage and snapshots are reclaimed. It only
+	// occurs for requests with Spec.Mode set to DeleteBackupRequestModeSoft.
+	DeleteBackupRequestPhasePendingDeletion DeleteBackupRequestPhase = "PendingDeletion"
+	// DeleteBackupRequestPhaseProcessed means the request has been processed completely.
+	DeleteBackupRequestPhaseProcessed DeleteBackupRequestPhase = "Processed"
+)
+
+// DeleteBackupRequestStatus captures the current status of an Ark DeleteBackupRequest.
+type DeleteBackupRequestStatus struct {
+	Phase  DeleteBackupRequestPhase `json:"phase,omitempty"`
+	Errors []string                 `json:"errors,omitempty"`
+
+	// RecoverableUntil is the time at which a Soft-mode request's recovery window expires and
+	// the backup becomes eligible for its storage and snapshots to actually be reclaimed. It is
+	// unset for Immediate-mode requests.
+	// +optional
+	RecoverableUntil *metav1.Time `json:"recoverableUntil,omitempty"`
+
+	// PreviousBackupPhase is the backup's phase immediately before it was tombstoned to
+	// PendingDeletion. Undelete restores the backup to this phase rather than assuming it was
+	// always Completed. It is unset for Immediate-mode requests, which never tombstone the
+	// backup.
+	// +optional
+	PreviousBackupPhase BackupPhase `json:"previousBackupPhase,omitempty"`
+
+	// SnapshotResults tracks the retry state of each persistent volume snapshot being deleted as
+	// part of this request. It's only populated once the backup's snapshots have started being
+	// reclaimed.
+	// +optional
+	SnapshotResults []SnapshotDeleteResult `json:"snapshotResults,omitempty"`
+}
+
+// SnapshotDeletePhase is a string representation of the state of deleting a single persistent
+// volume snapshot as part of a DeleteBackupRequest.
+type SnapshotDeletePhase string
+
+const (
+	// SnapshotDeletePhaseRetrying means the snapshot's deletion failed with a transient error and
+	// will be attempted again after NextRetryTime.
+	SnapshotDeletePhaseRetrying SnapshotDeletePhase = "Retrying"
+	// SnapshotDeletePhaseAccessDenied means the provider reported the snapshot as locked or
+	// otherwise protected. It will not be retried.
+	SnapshotDeletePhaseAccessDenied SnapshotDeletePhase = "AccessDenied"
+	// SnapshotDeletePhaseCompleted means the snapshot has been deleted, or was already gone.
+	SnapshotDeletePhaseCompleted SnapshotDeletePhase = "Completed"
+)
+
+// SnapshotDeleteResult tracks the retry state of deleting a single persistent volume snapshot as
+// part of a DeleteBackupRequest.
+type SnapshotDeleteResult struct {
+	PersistentVolumeName string              `json:"persistentVolumeName"`
+	SnapshotID           string              `json:"snapshotID"`
+	Phase                SnapshotDeletePhase `json:"phase,omitempty"`
+	Attempts             int                 `json:"attempts,omitempty"`
+
+	// NextRetryTime is when this snapshot's deletion will next be attempted. It's unset once the
+	// snapshot reaches SnapshotDeletePhaseCompleted or SnapshotDeletePhaseAccessDenied.
+	// +optional
+	NextRetryTime *metav1.Time `json:"nextRetryTime,omitempty"`
+
+	// Error is the message from the most recent failed deletion attempt, if any.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DeleteBackupRequestList is a list of DeleteBackupRequests.
+type DeleteBackupRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DeleteBackupRequest `json:"items"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DeleteBackupsRequest is an Ark resource that represents a request to delete a batch of Backups
+// as a single unit, analogous to an S3 DeleteObjects call.
+type DeleteBackupsRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DeleteBackupsRequestSpec   `json:"spec,omitempty"`
+	Status DeleteBackupsRequestStatus `json:"status,omitempty"`
+}
+
+// BackupDeleteTarget identifies a single backup to include in a DeleteBackupsRequest.
+type BackupDeleteTarget struct {
+	BackupName string `json:"backupName"`
+	UID        string `json:"uid,omitempty"`
+}
+
+// DeleteBackupsRequestSpec is the specification for a batch backup deletion.
+type DeleteBackupsRequestSpec struct {
+	Targets []BackupDeleteTarget `json:"targets"`
+
+	// Quiet suppresses successful entries from Status.Results, leaving only the ones that
+	// encountered an error, mirroring the Quiet mode of S3's DeleteObjects API.
+	// +optional
+	Quiet bool `json:"quiet,omitempty"`
+}
+
+// DeleteBackupsRequestPhase is a string representation of the lifecycle phase of an Ark
+// DeleteBackupsRequest.
+type DeleteBackupsRequestPhase string
+
+const (
+	// DeleteBackupsRequestPhaseNew means the request has not been processed yet.
+	DeleteBackupsRequestPhaseNew DeleteBackupsRequestPhase = "New"
+	// DeleteBackupsRequestPhaseInProgress means the request is being processed.
+	DeleteBackupsRequestPhaseInProgress DeleteBackupsRequestPhase = "InProgress"
+	// DeleteBackupsRequestPhaseProcessed means every target in the request has been attempted
+	// and the outcome of each recorded in Status.Results (only the failed ones when Spec.Quiet
+	// is set).
+	DeleteBackupsRequestPhaseProcessed DeleteBackupsRequestPhase = "Processed"
+)
+
+// BackupDeleteResult is the outcome of attempting to delete a single backup as part of a
+// DeleteBackupsRequest, modeled on the per-key result entries of S3's DeleteObjects API.
+type BackupDeleteResult struct {
+	Key       string `json:"key"`
+	VersionID string `json:"versionId,omitempty"`
+	// Error is empty if the backup was deleted successfully. A backup that doesn't exist is
+	// reported with the error "backup not found".
+	Error string `json:"error,omitempty"`
+}
+
+// DeleteBackupsRequestStatus captures the current status of an Ark DeleteBackupsRequest.
+type DeleteBackupsRequestStatus struct {
+	Phase   DeleteBackupsRequestPhase `json:"phase,omitempty"`
+	Results []BackupDeleteResult      `json:"results,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DeleteBackupsRequestList is a list of DeleteBackupsRequests.
+type DeleteBackupsRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DeleteBackupsRequest `json:"items"`
+}
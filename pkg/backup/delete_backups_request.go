@@ -0,0 +1,36 @@
+/*
+Copyright 2018 the Heptio Ark contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/heptio/ark/pkg/apis/ark/v1"
+)
+
+// NewDeleteBackupsRequest creates a DeleteBackupsRequest for the given batch of backup targets.
+func NewDeleteBackupsRequest(targets []v1.BackupDeleteTarget, quiet bool) *v1.DeleteBackupsRequest {
+	return &v1.DeleteBackupsRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "backups-",
+		},
+		Spec: v1.DeleteBackupsRequestSpec{
+			Targets: targets,
+			Quiet:   quiet,
+		},
+	}
+}
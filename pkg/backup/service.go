@@ -0,0 +1,41 @@
+/*
+Copyright 2017 the Heptio Ark contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"io"
+
+	"github.com/heptio/ark/pkg/apis/ark/v1"
+)
+
+// Service contains methods for working with backups in object storage.
+type Service interface {
+	// UploadBackup uploads the tarball and log for a backup to object storage.
+	UploadBackup(bucket, backupName string, metadata, backupContents, log io.Reader) error
+
+	// DownloadBackup downloads a tarball of a backup from object storage.
+	DownloadBackup(bucket, backupName string) (io.ReadCloser, error)
+
+	// DeleteBackupDir deletes all files in object storage for the given backup. If ctx is
+	// cancelled before the deletion completes, implementations should abandon the operation
+	// rather than continue mutating storage on behalf of a caller that may no longer own it.
+	DeleteBackupDir(ctx context.Context, bucket, backupName string) error
+
+	// GetBackup gets the specified api object from object storage.
+	GetBackup(bucket, name string) (*v1.Backup, error)
+}
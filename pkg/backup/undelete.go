@@ -0,0 +1,79 @@
+/*
+Copyright 2018 the Heptio Ark contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"encoding/json"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/heptio/ark/pkg/apis/ark/v1"
+	arkv1client "github.com/heptio/ark/pkg/generated/clientset/versioned/typed/ark/v1"
+)
+
+// Undelete reverses a Soft-mode DeleteBackupRequest that's still within its recovery window: it
+// clears the backup's tombstone and removes the request so the deletion controller's reaper won't
+// later reclaim the backup's storage and snapshots. This repository defines no `ark backup
+// undelete` command; Undelete is available only as this library function.
+func Undelete(backupClient arkv1client.BackupsGetter, requestClient arkv1client.DeleteBackupRequestsGetter, namespace string, req *v1.DeleteBackupRequest) error {
+	if req.Status.Phase != v1.DeleteBackupRequestPhasePendingDeletion {
+		return errors.Errorf("DeleteBackupRequest %q is not pending deletion", req.Name)
+	}
+
+	backup, err := backupClient.Backups(namespace).Get(req.Spec.BackupName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "error getting backup")
+	}
+
+	before, err := json.Marshal(backup)
+	if err != nil {
+		return errors.Wrap(err, "error marshalling original Backup")
+	}
+
+	previousPhase := req.Status.PreviousBackupPhase
+	if previousPhase == "" {
+		// PreviousBackupPhase is unset when the backup had no phase at the time it was
+		// tombstoned; fall back to Completed.
+		previousPhase = v1.BackupPhaseCompleted
+	}
+
+	updated := backup.DeepCopy()
+	updated.Status.Phase = previousPhase
+
+	after, err := json.Marshal(updated)
+	if err != nil {
+		return errors.Wrap(err, "error marshalling updated Backup")
+	}
+
+	patchBytes, err := jsonpatch.CreateMergePatch(before, after)
+	if err != nil {
+		return errors.Wrap(err, "error creating merge patch")
+	}
+
+	if _, err := backupClient.Backups(namespace).Patch(backup.Name, types.MergePatchType, patchBytes); err != nil {
+		return errors.Wrap(err, "error clearing backup's tombstone")
+	}
+
+	if err := requestClient.DeleteBackupRequests(namespace).Delete(req.Name, nil); err != nil {
+		return errors.Wrap(err, "error deleting DeleteBackupRequest")
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2018 the Heptio Ark contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/heptio/ark/pkg/apis/ark/v1"
+	"github.com/heptio/ark/pkg/generated/clientset/versioned/fake"
+	arktest "github.com/heptio/ark/pkg/util/test"
+)
+
+func TestUndelete(t *testing.T) {
+	t.Run("not pending deletion", func(t *testing.T) {
+		client := fake.NewSimpleClientset()
+
+		req := &v1.DeleteBackupRequest{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "heptio-ark", Name: "foo-1"},
+			Status:     v1.DeleteBackupRequestStatus{Phase: v1.DeleteBackupRequestPhaseProcessed},
+		}
+
+		err := Undelete(client.ArkV1(), client.ArkV1(), "heptio-ark", req)
+		assert.Error(t, err)
+	})
+
+	t.Run("restores the backup's phase from before it was tombstoned", func(t *testing.T) {
+		backup := arktest.NewTestBackup().WithName("foo").WithPhase(v1.BackupPhasePendingDeletion).Backup
+
+		req := &v1.DeleteBackupRequest{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "heptio-ark", Name: "foo-1"},
+			Spec:       v1.DeleteBackupRequestSpec{BackupName: "foo"},
+			Status: v1.DeleteBackupRequestStatus{
+				Phase:               v1.DeleteBackupRequestPhasePendingDeletion,
+				PreviousBackupPhase: v1.BackupPhasePartiallyFailed,
+			},
+		}
+
+		client := fake.NewSimpleClientset(backup, req)
+
+		err := Undelete(client.ArkV1(), client.ArkV1(), "heptio-ark", req)
+		require.NoError(t, err)
+
+		updated, err := client.ArkV1().Backups("heptio-ark").Get("foo", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, v1.BackupPhasePartiallyFailed, updated.Status.Phase)
+
+		_, err = client.ArkV1().DeleteBackupRequests("heptio-ark").Get("foo-1", metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("falls back to Completed when no previous phase was recorded", func(t *testing.T) {
+		backup := arktest.NewTestBackup().WithName("foo").WithPhase(v1.BackupPhasePendingDeletion).Backup
+
+		req := &v1.DeleteBackupRequest{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "heptio-ark", Name: "foo-1"},
+			Spec:       v1.DeleteBackupRequestSpec{BackupName: "foo"},
+			Status:     v1.DeleteBackupRequestStatus{Phase: v1.DeleteBackupRequestPhasePendingDeletion},
+		}
+
+		client := fake.NewSimpleClientset(backup, req)
+
+		err := Undelete(client.ArkV1(), client.ArkV1(), "heptio-ark", req)
+		require.NoError(t, err)
+
+		updated, err := client.ArkV1().Backups("heptio-ark").Get("foo", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, v1.BackupPhaseCompleted, updated.Status.Phase)
+	})
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2018 the Heptio Ark contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudprovider
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// ErrSnapshotNotFound is returned by a SnapshotDeleter when the requested snapshot doesn't exist.
+// Callers should treat this as a successful, idempotent deletion rather than an error -- the
+// snapshot is already gone, whether this call or an earlier, interrupted one removed it.
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+// ErrSnapshotLocked is returned by a SnapshotDeleter when the snapshot can't be deleted because
+// the provider has it locked or otherwise protected, for example by a retention rule or an
+// in-progress copy. Callers should surface this to the user rather than retrying indefinitely,
+// since retrying won't succeed until something outside Ark releases the lock.
+var ErrSnapshotLocked = errors.New("snapshot is locked and cannot be deleted")
+
+// ErrTransient wraps a provider error that's expected to be retryable, such as throttling or a
+// network timeout. Callers should retry the deletion with backoff rather than giving up.
+type ErrTransient struct {
+	// Cause is the underlying error returned by the provider.
+	Cause error
+}
+
+func (e ErrTransient) Error() string {
+	return errors.Wrap(e.Cause, "transient error deleting snapshot").Error()
+}
+
+// SnapshotDeleter is implemented by snapshot providers that classify their DeleteSnapshot errors
+// using ErrSnapshotNotFound, ErrSnapshotLocked, and ErrTransient, so that callers can apply
+// retry and idempotency semantics without depending on provider-specific error messages.
+// SnapshotService satisfies this interface.
+type SnapshotDeleter interface {
+	// DeleteSnapshot deletes the specified snapshot. If ctx is cancelled before the deletion
+	// completes, implementations should abandon the operation rather than continue mutating
+	// storage on behalf of a caller that may no longer own it.
+	DeleteSnapshot(ctx context.Context, snapshotID string) error
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2017 the Heptio Ark contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudprovider
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// SnapshotService contains methods for working with disk snapshots as part of backups and restores.
+type SnapshotService interface {
+	// CreateSnapshot creates a snapshot of the specified volume, and applies the provided
+	// set of tags to the snapshot.
+	CreateSnapshot(volumeID, volumeAZ string, tags map[string]string) (snapshotID string, err error)
+
+	// DeleteSnapshot deletes the specified snapshot. If ctx is cancelled before the deletion
+	// completes, implementations should abandon the operation rather than continue mutating
+	// storage on behalf of a caller that may no longer own it.
+	DeleteSnapshot(ctx context.Context, snapshotID string) error
+
+	// GetVolumeID returns the cloud provider specific identifier for the PersistentVolume.
+	GetVolumeID(pv *unstructured.Unstructured) (string, error)
+
+	// SetVolumeID sets the cloud provider specific identifier for the PersistentVolume and returns
+	// a new PersistentVolume with the change applied.
+	SetVolumeID(pv *unstructured.Unstructured, volumeID string) (*unstructured.Unstructured, error)
+}
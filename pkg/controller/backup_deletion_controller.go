@@ -0,0 +1,582 @@
+/*
+Copyright 2018 the Heptio Ark contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/heptio/ark/pkg/apis/ark/v1"
+	pkgbackup "github.com/heptio/ark/pkg/backup"
+	"github.com/heptio/ark/pkg/cloudprovider"
+	arkv1client "github.com/heptio/ark/pkg/generated/clientset/versioned/typed/ark/v1"
+	informers "github.com/heptio/ark/pkg/generated/informers/externalversions/ark/v1"
+	listers "github.com/heptio/ark/pkg/generated/listers/ark/v1"
+	"github.com/heptio/ark/pkg/util/backoff"
+)
+
+// deleteExpiredRequestsMaxAge is how long a Processed DeleteBackupRequest is kept around before
+// the controller's periodic reaper removes it.
+const deleteExpiredRequestsMaxAge = 24 * time.Hour
+
+type backupDeletionController struct {
+	*genericController
+
+	deleteBackupRequestClient arkv1client.DeleteBackupRequestsGetter
+	deleteBackupRequestLister listers.DeleteBackupRequestLister
+	backupClient              arkv1client.BackupsGetter
+	restoreClient             arkv1client.RestoresGetter
+	restoreLister             listers.RestoreLister
+	bslLister                 listers.BackupStorageLocationLister
+	scheduleLister            listers.ScheduleLister
+	snapshotService           cloudprovider.SnapshotService
+	backupService             pkgbackup.Service
+	bucket                    string
+	eventRecorder             record.EventRecorder
+	deletionLock              DeletionLock
+
+	processRequestFunc   func(*v1.DeleteBackupRequest) error
+	clock                clock.Clock
+	snapshotRetryBackoff backoff.Backoff
+
+	// deletionRecoveryWindow is how long a Soft-mode DeleteBackupRequest's backup is tombstoned
+	// before its storage and snapshots are actually reclaimed. Zero means a Soft-mode request is
+	// processed as though it were Immediate.
+	deletionRecoveryWindow time.Duration
+}
+
+// NewBackupDeletionController creates a new backupDeletionController.
+func NewBackupDeletionController(
+	logger logrus.FieldLogger,
+	deleteBackupRequestInformer informers.DeleteBackupRequestInformer,
+	deleteBackupRequestClient arkv1client.DeleteBackupRequestsGetter,
+	backupClient arkv1client.BackupsGetter,
+	snapshotService cloudprovider.SnapshotService,
+	backupService pkgbackup.Service,
+	bucket string,
+	restoreInformer informers.RestoreInformer,
+	restoreClient arkv1client.RestoresGetter,
+	deletionRecoveryWindow time.Duration,
+	backupStorageLocationInformer informers.BackupStorageLocationInformer,
+	scheduleInformer informers.ScheduleInformer,
+	eventRecorder record.EventRecorder,
+	kubeClient kubernetes.Interface,
+) Interface {
+	c := &backupDeletionController{
+		genericController:         newGenericController("backup-deletion", logger),
+		deleteBackupRequestClient: deleteBackupRequestClient,
+		deleteBackupRequestLister: deleteBackupRequestInformer.Lister(),
+		backupClient:              backupClient,
+		restoreClient:             restoreClient,
+		restoreLister:             restoreInformer.Lister(),
+		bslLister:                 backupStorageLocationInformer.Lister(),
+		scheduleLister:            scheduleInformer.Lister(),
+		snapshotService:           snapshotService,
+		backupService:             backupService,
+		bucket:                    bucket,
+		deletionRecoveryWindow:    deletionRecoveryWindow,
+		eventRecorder:             eventRecorder,
+		deletionLock:              NewConfigMapDeletionLock(kubeClient, defaultHolderIdentity()),
+
+		clock:                clock.RealClock{},
+		snapshotRetryBackoff: backoff.NewDefault(),
+	}
+
+	c.syncHandler = c.processQueueItem
+	c.processRequestFunc = c.processRequest
+	c.resyncFunc = c.resync
+	c.resyncPeriod = time.Hour
+	c.cacheSyncWaiters = append(c.cacheSyncWaiters,
+		deleteBackupRequestInformer.Informer().HasSynced,
+		restoreInformer.Informer().HasSynced,
+		backupStorageLocationInformer.Informer().HasSynced,
+		scheduleInformer.Informer().HasSynced,
+	)
+
+	deleteBackupRequestInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.enqueue,
+			UpdateFunc: func(_, obj interface{}) { c.enqueue(obj) },
+		},
+	)
+
+	return c
+}
+
+func (c *backupDeletionController) processQueueItem(key string) error {
+	ns, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return errors.Wrap(err, "error splitting queue key")
+	}
+
+	log := c.logger.WithField("key", key)
+
+	req, err := c.deleteBackupRequestLister.DeleteBackupRequests(ns).Get(name)
+	if apierrors.IsNotFound(err) {
+		log.Debug("Unable to find DeleteBackupRequest")
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "error getting DeleteBackupRequest")
+	}
+
+	switch req.Status.Phase {
+	case v1.DeleteBackupRequestPhaseProcessed:
+		log.Debug("DeleteBackupRequest has already been processed, skipping")
+		return nil
+	case v1.DeleteBackupRequestPhasePendingDeletion:
+		log.Debug("DeleteBackupRequest is pending deletion, waiting for its recovery window to elapse")
+		return nil
+	}
+
+	return c.processRequestFunc(req)
+}
+
+// processRequest takes a DeleteBackupRequest through to completion. It first fetches the backup
+// and runs the BSL/schedule/restore validation so that an already-doomed request fails fast
+// without ever transitioning to InProgress. Past that, it patches the request to InProgress. For
+// an Immediate-mode request (the default), it then deletes the backup's restores, snapshots, and
+// object storage files, deletes the backup itself, and finally patches the request to Processed,
+// recording any errors encountered along the way on Status.Errors; if a snapshot is still
+// retrying after a transient error, it instead returns an error so the request is requeued, and
+// does not patch it to Processed. For a Soft-mode request with a non-zero recovery window
+// configured, it instead tombstones the backup and moves the request to PendingDeletion;
+// reapExpiredTombstones later finishes the job once the recovery window has elapsed, unless the
+// tombstone is cleared first by pkg/backup's Undelete.
+func (c *backupDeletionController) processRequest(req *v1.DeleteBackupRequest) error {
+	log := c.logger.WithFields(logrus.Fields{
+		"namespace": req.Namespace,
+		"name":      req.Name,
+	})
+
+	backup, err := c.backupClient.Backups(req.Namespace).Get(req.Spec.BackupName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return c.finalizeProcessed(req, []string{"backup not found"})
+	}
+	if err != nil {
+		return c.finalizeProcessed(req, []string{fmt.Sprintf("error getting backup: %v", err)})
+	}
+
+	if len(backup.Status.VolumeBackups) > 0 && c.snapshotService == nil {
+		return c.finalizeProcessed(req, []string{"unable to delete backup because it includes PV snapshots and Ark is not configured with a PersistentVolumeProvider"})
+	}
+
+	if reason, message, blocked := c.validateForDeletion(backup); blocked {
+		log.WithFields(logrus.Fields{"reason": reason, "message": message}).Warn("Refusing to delete backup")
+		c.recordDeletionBlockedEvent(backup, reason, message)
+		return c.finalizeProcessed(req, []string{fmt.Sprintf("%s: %s", reason, message)})
+	}
+
+	req, err = patchDeleteBackupRequest(req, c.deleteBackupRequestClient, func(r *v1.DeleteBackupRequest) {
+		r.Status.Phase = v1.DeleteBackupRequestPhaseInProgress
+	})
+	if err != nil {
+		return errors.Wrap(err, "error patching DeleteBackupRequest")
+	}
+
+	if req.Spec.Mode == v1.DeleteBackupRequestModeSoft && c.deletionRecoveryWindow > 0 {
+		return c.tombstoneBackup(log, req, backup)
+	}
+
+	// Losing the race for the deletion lock -- most commonly because another replica already
+	// holds it for this backup -- isn't a failure of this request; the replica that holds the
+	// lock will carry it through to Processed. Returning an error here just requeues req so this
+	// replica can try again later, rather than finalizing it out from under the lock holder.
+	lockCtx, release, err := c.acquireDeletionLock(req)
+	if err != nil {
+		return errors.Wrap(err, "error acquiring deletion lock")
+	}
+	defer release()
+
+	reclaimErrs, retryable := c.reclaimBackup(lockCtx, log, req, backup)
+
+	if retryable {
+		log.WithField("errors", reclaimErrs).Warn("One or more snapshots could not be deleted yet, will retry")
+		return errors.New("one or more snapshots have not yet been deleted")
+	}
+
+	return c.finalizeProcessed(req, reclaimErrs)
+}
+
+// acquireDeletionLock takes exclusive ownership, across all BackupDeletionController replicas, of
+// reclaiming req's backup's storage and snapshots, keyed by the backup's name and UID.
+func (c *backupDeletionController) acquireDeletionLock(req *v1.DeleteBackupRequest) (context.Context, func(), error) {
+	key := fmt.Sprintf("%s-%s", req.Spec.BackupName, req.Labels[v1.BackupUIDLabel])
+	return c.deletionLock.Acquire(context.Background(), req.Namespace, key)
+}
+
+// tombstoneBackup marks backup as pending deletion in object storage and moves req to
+// PendingDeletion, without yet reclaiming the backup's storage or snapshots. It does not delete
+// the DeleteBackupRequest's snapshots/storage/restores and does not patch req to Processed -- that
+// happens later, via reapExpiredTombstones, once the recovery window elapses.
+func (c *backupDeletionController) tombstoneBackup(log logrus.FieldLogger, req *v1.DeleteBackupRequest, backup *v1.Backup) error {
+	previousPhase := backup.Status.Phase
+
+	if _, err := patchBackup(backup, c.backupClient, func(b *v1.Backup) {
+		b.Status.Phase = v1.BackupPhasePendingDeletion
+	}); err != nil {
+		log.WithError(err).Error("Error patching Backup's phase to PendingDeletion")
+	}
+
+	recoverableUntil := metav1.NewTime(c.clock.Now().Add(c.deletionRecoveryWindow))
+
+	if _, err := patchDeleteBackupRequest(req, c.deleteBackupRequestClient, func(r *v1.DeleteBackupRequest) {
+		r.Status.Phase = v1.DeleteBackupRequestPhasePendingDeletion
+		r.Status.RecoverableUntil = &recoverableUntil
+		r.Status.PreviousBackupPhase = previousPhase
+	}); err != nil {
+		return errors.Wrap(err, "error patching DeleteBackupRequest to PendingDeletion")
+	}
+
+	return nil
+}
+
+// reclaimBackup deletes backup's existing restores and snapshots, and, once every snapshot has
+// either been deleted or given up on as AccessDenied, its object storage files and the backup
+// itself. It returns a slice of error strings for anything that couldn't be deleted, and whether
+// one or more snapshots are still retrying after a transient error -- if so, the backup's object
+// storage files and the backup itself are deliberately left alone, and the caller should arrange
+// for reclaimBackup to be called again later rather than treating req as Processed. ctx is
+// cancelled if this replica loses ownership of backup's deletion lock partway through -- for
+// example because the API server became unreachable -- in which case the snapshot and object
+// storage calls abandon the operation rather than race another replica that has taken over.
+func (c *backupDeletionController) reclaimBackup(ctx context.Context, log logrus.FieldLogger, req *v1.DeleteBackupRequest, backup *v1.Backup) (errs []string, retryable bool) {
+	if _, err := patchBackup(backup, c.backupClient, func(b *v1.Backup) {
+		b.Status.Phase = v1.BackupPhaseDeleting
+	}); err != nil {
+		log.WithError(err).Error("Error patching Backup's phase to Deleting")
+	}
+
+	if restoreErrs := c.deleteExistingRestores(req.Namespace, req.Spec.BackupName); len(restoreErrs) > 0 {
+		errs = append(errs, restoreErrs...)
+	}
+
+	if c.snapshotService != nil && len(backup.Status.VolumeBackups) > 0 {
+		results, snapshotErrs := c.deleteSnapshots(ctx, req, backup)
+		errs = append(errs, snapshotErrs...)
+
+		// req.Name is empty for the synthetic, never-persisted DeleteBackupRequests that back a
+		// DeleteBackupsRequest batch target (see backupDeletionsController.deleteTarget); there's
+		// no object to patch the retry state onto, so skip it rather than failing every Patch
+		// call against that empty name.
+		if req.Name != "" {
+			if _, err := patchDeleteBackupRequest(req, c.deleteBackupRequestClient, func(r *v1.DeleteBackupRequest) {
+				r.Status.SnapshotResults = results
+			}); err != nil {
+				log.WithError(err).Error("Error patching DeleteBackupRequest's snapshot results")
+			}
+		}
+
+		for _, result := range results {
+			if result.Phase == v1.SnapshotDeletePhaseRetrying {
+				return errs, true
+			}
+		}
+	}
+
+	if err := c.backupService.DeleteBackupDir(ctx, c.bucket, req.Spec.BackupName); err != nil {
+		errs = append(errs, fmt.Sprintf("error deleting backup from object storage: %v", err))
+	}
+
+	if err := c.backupClient.Backups(req.Namespace).Delete(req.Spec.BackupName, nil); err != nil && !apierrors.IsNotFound(err) {
+		errs = append(errs, fmt.Sprintf("error deleting backup: %v", err))
+	}
+
+	return errs, false
+}
+
+// deleteSnapshots attempts to delete every one of backup's volume snapshots that hasn't already
+// completed or been given up on, classifying each error using the cloudprovider.SnapshotDeleter
+// error conventions: a missing snapshot is treated as already deleted, a locked snapshot is marked
+// AccessDenied and not retried, and any other error is treated as transient and retried later with
+// exponential backoff. It returns the updated per-snapshot results, in no particular order, along
+// with a slice of error strings describing anything that isn't yet Completed.
+func (c *backupDeletionController) deleteSnapshots(ctx context.Context, req *v1.DeleteBackupRequest, backup *v1.Backup) ([]v1.SnapshotDeleteResult, []string) {
+	existing := make(map[string]v1.SnapshotDeleteResult)
+	for _, result := range req.Status.SnapshotResults {
+		existing[result.SnapshotID] = result
+	}
+
+	now := c.clock.Now()
+
+	var errs []string
+	results := make([]v1.SnapshotDeleteResult, 0, len(backup.Status.VolumeBackups))
+
+	for pvName, volumeBackup := range backup.Status.VolumeBackups {
+		result := existing[volumeBackup.SnapshotID]
+		result.PersistentVolumeName = pvName
+		result.SnapshotID = volumeBackup.SnapshotID
+
+		if result.Phase == v1.SnapshotDeletePhaseCompleted || result.Phase == v1.SnapshotDeletePhaseAccessDenied {
+			results = append(results, result)
+			continue
+		}
+
+		if result.NextRetryTime != nil && now.Before(result.NextRetryTime.Time) {
+			errs = append(errs, fmt.Sprintf("snapshot %q for persistent volume %q will be retried at %s: %s", volumeBackup.SnapshotID, pvName, result.NextRetryTime.Time.Format(time.RFC3339), result.Error))
+			results = append(results, result)
+			continue
+		}
+
+		result.Attempts++
+
+		switch err := c.snapshotService.DeleteSnapshot(ctx, volumeBackup.SnapshotID); errors.Cause(err) {
+		case nil, cloudprovider.ErrSnapshotNotFound:
+			result.Phase = v1.SnapshotDeletePhaseCompleted
+			result.Error = ""
+			result.NextRetryTime = nil
+		case cloudprovider.ErrSnapshotLocked:
+			result.Phase = v1.SnapshotDeletePhaseAccessDenied
+			result.Error = err.Error()
+			result.NextRetryTime = nil
+			errs = append(errs, fmt.Sprintf("snapshot %q for persistent volume %q is locked and was not deleted: %v", volumeBackup.SnapshotID, pvName, err))
+		default:
+			result.Phase = v1.SnapshotDeletePhaseRetrying
+			result.Error = err.Error()
+			next := metav1.NewTime(now.Add(c.snapshotRetryBackoff.Next(result.Attempts)))
+			result.NextRetryTime = &next
+			errs = append(errs, fmt.Sprintf("error deleting snapshot %q for persistent volume %q, will retry at %s: %v", volumeBackup.SnapshotID, pvName, next.Time.Format(time.RFC3339), err))
+		}
+
+		results = append(results, result)
+	}
+
+	return results, errs
+}
+
+// finalizeProcessed patches req to Processed with the given errors and, if there were none,
+// deletes any other DeleteBackupRequests for the same backup.
+func (c *backupDeletionController) finalizeProcessed(req *v1.DeleteBackupRequest, errs []string) error {
+	log := c.logger.WithFields(logrus.Fields{
+		"namespace": req.Namespace,
+		"name":      req.Name,
+	})
+
+	req, err := patchDeleteBackupRequest(req, c.deleteBackupRequestClient, func(r *v1.DeleteBackupRequest) {
+		r.Status.Phase = v1.DeleteBackupRequestPhaseProcessed
+		r.Status.Errors = errs
+	})
+	if err != nil {
+		log.WithError(err).Error("Error patching DeleteBackupRequest's final status")
+		return nil
+	}
+
+	if len(errs) > 0 {
+		return nil
+	}
+
+	uid := req.Labels[v1.BackupUIDLabel]
+	if err := c.deleteBackupRequestClient.DeleteBackupRequests(req.Namespace).DeleteCollection(
+		nil,
+		pkgbackup.NewDeleteBackupRequestListOptions(req.Spec.BackupName, uid),
+	); err != nil {
+		log.WithError(err).Error("Error deleting old DeleteBackupRequests")
+	}
+
+	return nil
+}
+
+// reapExpiredTombstones finishes processing any PendingDeletion requests whose recovery window
+// has elapsed, reclaiming the backup's storage and snapshots and moving the request to Processed.
+func (c *backupDeletionController) reapExpiredTombstones() {
+	log := c.logger.WithField("resync", "reapExpiredTombstones")
+
+	requests, err := c.deleteBackupRequestLister.List(labels.Everything())
+	if err != nil {
+		log.WithError(err).Error("Error listing DeleteBackupRequests")
+		return
+	}
+
+	now := c.clock.Now()
+
+	for _, req := range requests {
+		if req.Status.Phase != v1.DeleteBackupRequestPhasePendingDeletion {
+			continue
+		}
+		if req.Status.RecoverableUntil == nil || now.Before(req.Status.RecoverableUntil.Time) {
+			continue
+		}
+
+		reqLog := log.WithFields(logrus.Fields{"namespace": req.Namespace, "name": req.Name})
+
+		backup, err := c.backupClient.Backups(req.Namespace).Get(req.Spec.BackupName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			c.finalizeProcessed(req, []string{"backup not found"})
+			continue
+		}
+		if err != nil {
+			reqLog.WithError(err).Error("Error getting backup whose tombstone recovery window has expired")
+			continue
+		}
+
+		lockCtx, release, err := c.acquireDeletionLock(req)
+		if err != nil {
+			reqLog.WithError(err).Error("Error acquiring deletion lock")
+			continue
+		}
+
+		reqLog.Debug("Recovery window has expired, reclaiming tombstoned backup")
+		errs, retryable := c.reclaimBackup(lockCtx, reqLog, req, backup)
+		release()
+
+		if retryable {
+			reqLog.WithField("errors", errs).Warn("One or more snapshots could not be deleted yet, will retry on the next resync")
+			continue
+		}
+
+		c.finalizeProcessed(req, errs)
+	}
+}
+
+// deleteExistingRestores deletes every Restore that was created from the named backup, returning
+// a slice of error strings for any that couldn't be deleted.
+func (c *backupDeletionController) deleteExistingRestores(namespace, backupName string) []string {
+	var errs []string
+
+	restores, err := c.restoreLister.Restores(namespace).List(labels.Everything())
+	if err != nil {
+		return append(errs, fmt.Sprintf("error listing restores: %v", err))
+	}
+
+	for _, restore := range restores {
+		if restore.Spec.BackupName != backupName {
+			continue
+		}
+
+		if err := c.restoreClient.Restores(namespace).Delete(restore.Name, nil); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Sprintf("error deleting restore %q: %v", restore.Name, err))
+		}
+	}
+
+	return errs
+}
+
+// resync is invoked periodically: it reaps old Processed requests and advances any tombstoned
+// backups whose recovery window has elapsed.
+func (c *backupDeletionController) resync() {
+	c.deleteExpiredRequests()
+	c.reapExpiredTombstones()
+}
+
+// deleteExpiredRequests deletes Processed DeleteBackupRequests that are older than
+// deleteExpiredRequestsMaxAge. It's invoked periodically so that old requests don't accumulate
+// forever.
+func (c *backupDeletionController) deleteExpiredRequests() {
+	log := c.logger.WithField("resync", "deleteExpiredRequests")
+
+	requests, err := c.deleteBackupRequestLister.List(labels.Everything())
+	if err != nil {
+		log.WithError(err).Error("Error listing DeleteBackupRequests")
+		return
+	}
+
+	now := c.clock.Now()
+
+	for _, req := range requests {
+		if req.Status.Phase != v1.DeleteBackupRequestPhaseProcessed {
+			continue
+		}
+
+		if now.Sub(req.CreationTimestamp.Time) < deleteExpiredRequestsMaxAge {
+			continue
+		}
+
+		log.WithFields(logrus.Fields{
+			"namespace": req.Namespace,
+			"name":      req.Name,
+		}).Debug("Deleting expired DeleteBackupRequest")
+
+		if err := c.deleteBackupRequestClient.DeleteBackupRequests(req.Namespace).Delete(req.Name, nil); err != nil {
+			log.WithError(err).WithFields(logrus.Fields{
+				"namespace": req.Namespace,
+				"name":      req.Name,
+			}).Error("Error deleting expired DeleteBackupRequest")
+		}
+	}
+}
+
+// patchDeleteBackupRequest mutates req via the mutate func, computes a JSON merge patch between
+// the original and mutated objects, and applies it via the API, returning the patched object.
+func patchDeleteBackupRequest(req *v1.DeleteBackupRequest, client arkv1client.DeleteBackupRequestsGetter, mutate func(*v1.DeleteBackupRequest)) (*v1.DeleteBackupRequest, error) {
+	before, err := json.Marshal(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshalling original DeleteBackupRequest")
+	}
+
+	updated := req.DeepCopy()
+	mutate(updated)
+
+	after, err := json.Marshal(updated)
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshalling updated DeleteBackupRequest")
+	}
+
+	patchBytes, err := jsonpatch.CreateMergePatch(before, after)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating merge patch")
+	}
+
+	patched, err := client.DeleteBackupRequests(req.Namespace).Patch(req.Name, types.MergePatchType, patchBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return patched, nil
+}
+
+// patchBackup mutates backup via the mutate func, computes a JSON merge patch between the
+// original and mutated objects, and applies it via the API, returning the patched object.
+func patchBackup(backup *v1.Backup, client arkv1client.BackupsGetter, mutate func(*v1.Backup)) (*v1.Backup, error) {
+	before, err := json.Marshal(backup)
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshalling original Backup")
+	}
+
+	updated := backup.DeepCopy()
+	mutate(updated)
+
+	after, err := json.Marshal(updated)
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshalling updated Backup")
+	}
+
+	patchBytes, err := jsonpatch.CreateMergePatch(before, after)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating merge patch")
+	}
+
+	patched, err := client.Backups(backup.Namespace).Patch(backup.Name, types.MergePatchType, patchBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return patched, nil
+}
@@ -20,25 +20,31 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/heptio/ark/pkg/apis/ark/v1"
 	pkgbackup "github.com/heptio/ark/pkg/backup"
+	"github.com/heptio/ark/pkg/cloudprovider"
 	"github.com/heptio/ark/pkg/generated/clientset/versioned/fake"
 	informers "github.com/heptio/ark/pkg/generated/informers/externalversions"
 	"github.com/heptio/ark/pkg/util/kube"
 	arktest "github.com/heptio/ark/pkg/util/test"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/clock"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/watch"
+	kubefake "k8s.io/client-go/kubernetes/fake"
 	core "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
 )
 
 func TestBackupDeletionControllerControllerHasUpdateFunc(t *testing.T) {
@@ -64,6 +70,11 @@ func TestBackupDeletionControllerControllerHasUpdateFunc(t *testing.T) {
 		"bucket",
 		sharedInformers.Ark().V1().Restores(),
 		client.ArkV1(), // restoreClient
+		0,              // deletionRecoveryWindow
+		sharedInformers.Ark().V1().BackupStorageLocations(),
+		sharedInformers.Ark().V1().Schedules(),
+		record.NewFakeRecorder(100),
+		kubefake.NewSimpleClientset(),
 	).(*backupDeletionController)
 
 	// disable resync handler since we don't want to test it here
@@ -116,6 +127,11 @@ func TestBackupDeletionControllerProcessQueueItem(t *testing.T) {
 		"bucket",
 		sharedInformers.Ark().V1().Restores(),
 		client.ArkV1(), // restoreClient
+		0,              // deletionRecoveryWindow
+		sharedInformers.Ark().V1().BackupStorageLocations(),
+		sharedInformers.Ark().V1().Schedules(),
+		record.NewFakeRecorder(100),
+		kubefake.NewSimpleClientset(),
 	).(*backupDeletionController)
 
 	// Error splitting key
@@ -196,6 +212,11 @@ func setupBackupDeletionControllerTest(objects ...runtime.Object) *backupDeletio
 			"bucket",
 			sharedInformers.Ark().V1().Restores(),
 			client.ArkV1(), // restoreClient
+			0,              // deletionRecoveryWindow
+			sharedInformers.Ark().V1().BackupStorageLocations(),
+			sharedInformers.Ark().V1().Schedules(),
+			record.NewFakeRecorder(100),
+			kubefake.NewSimpleClientset(),
 		).(*backupDeletionController),
 
 		req: req,
@@ -208,9 +229,15 @@ func setupBackupDeletionControllerTest(objects ...runtime.Object) *backupDeletio
 
 func TestBackupDeletionControllerProcessRequest(t *testing.T) {
 	t.Run("patching to InProgress fails", func(t *testing.T) {
-		td := setupBackupDeletionControllerTest()
+		backup := arktest.NewTestBackup().WithName("foo").Backup
+
+		td := setupBackupDeletionControllerTest(backup)
 		defer td.backupService.AssertExpectations(t)
 
+		td.client.PrependReactor("get", "backups", func(action core.Action) (bool, runtime.Object, error) {
+			return true, backup, nil
+		})
+
 		td.client.PrependReactor("patch", "deletebackuprequests", func(action core.Action) (bool, runtime.Object, error) {
 			return true, nil, errors.New("bad")
 		})
@@ -235,12 +262,6 @@ func TestBackupDeletionControllerProcessRequest(t *testing.T) {
 		require.NoError(t, err)
 
 		expectedActions := []core.Action{
-			core.NewPatchAction(
-				v1.SchemeGroupVersion.WithResource("deletebackuprequests"),
-				td.req.Namespace,
-				td.req.Name,
-				[]byte(`{"status":{"phase":"InProgress"}}`),
-			),
 			core.NewGetAction(
 				v1.SchemeGroupVersion.WithResource("backups"),
 				td.req.Namespace,
@@ -275,12 +296,41 @@ func TestBackupDeletionControllerProcessRequest(t *testing.T) {
 		require.NoError(t, err)
 
 		expectedActions := []core.Action{
+			core.NewGetAction(
+				v1.SchemeGroupVersion.WithResource("backups"),
+				td.req.Namespace,
+				td.req.Spec.BackupName,
+			),
 			core.NewPatchAction(
 				v1.SchemeGroupVersion.WithResource("deletebackuprequests"),
 				td.req.Namespace,
 				td.req.Name,
-				[]byte(`{"status":{"phase":"InProgress"}}`),
+				[]byte(`{"status":{"errors":["unable to delete backup because it includes PV snapshots and Ark is not configured with a PersistentVolumeProvider"],"phase":"Processed"}}`),
 			),
+		}
+
+		assert.Equal(t, expectedActions, td.client.Actions())
+	})
+
+	t.Run("blocked, backup storage location no longer exists", func(t *testing.T) {
+		backup := arktest.NewTestBackup().WithName("foo").Backup
+		backup.Spec.StorageLocation = "missing-location"
+
+		td := setupBackupDeletionControllerTest(backup)
+		defer td.backupService.AssertExpectations(t)
+
+		td.client.PrependReactor("get", "backups", func(action core.Action) (bool, runtime.Object, error) {
+			return true, backup, nil
+		})
+
+		td.client.PrependReactor("patch", "deletebackuprequests", func(action core.Action) (bool, runtime.Object, error) {
+			return true, td.req, nil
+		})
+
+		err := td.controller.processRequest(td.req)
+		require.NoError(t, err)
+
+		expectedActions := []core.Action{
 			core.NewGetAction(
 				v1.SchemeGroupVersion.WithResource("backups"),
 				td.req.Namespace,
@@ -290,10 +340,208 @@ func TestBackupDeletionControllerProcessRequest(t *testing.T) {
 				v1.SchemeGroupVersion.WithResource("deletebackuprequests"),
 				td.req.Namespace,
 				td.req.Name,
-				[]byte(`{"status":{"errors":["unable to delete backup because it includes PV snapshots and Ark is not configured with a PersistentVolumeProvider"],"phase":"Processed"}}`),
+				[]byte(`{"status":{"errors":["ReferencesDeletedBackupStorageLocation: backup storage location \"missing-location\" no longer exists"],"phase":"Processed"}}`),
+			),
+		}
+
+		assert.Equal(t, expectedActions, td.client.Actions())
+	})
+
+	t.Run("blocked, backup storage location is read-only", func(t *testing.T) {
+		backup := arktest.NewTestBackup().WithName("foo").Backup
+		backup.Spec.StorageLocation = "read-only-location"
+
+		bsl := &v1.BackupStorageLocation{
+			ObjectMeta: metav1.ObjectMeta{Namespace: backup.Namespace, Name: "read-only-location"},
+			Spec:       v1.BackupStorageLocationSpec{AccessMode: v1.BackupStorageLocationAccessModeReadOnly},
+		}
+
+		td := setupBackupDeletionControllerTest(backup, bsl)
+		td.sharedInformers.Ark().V1().BackupStorageLocations().Informer().GetStore().Add(bsl)
+		defer td.backupService.AssertExpectations(t)
+
+		td.client.PrependReactor("get", "backups", func(action core.Action) (bool, runtime.Object, error) {
+			return true, backup, nil
+		})
+
+		td.client.PrependReactor("patch", "deletebackuprequests", func(action core.Action) (bool, runtime.Object, error) {
+			return true, td.req, nil
+		})
+
+		err := td.controller.processRequest(td.req)
+		require.NoError(t, err)
+
+		expectedActions := []core.Action{
+			core.NewGetAction(
+				v1.SchemeGroupVersion.WithResource("backups"),
+				td.req.Namespace,
+				td.req.Spec.BackupName,
+			),
+			core.NewPatchAction(
+				v1.SchemeGroupVersion.WithResource("deletebackuprequests"),
+				td.req.Namespace,
+				td.req.Name,
+				[]byte(`{"status":{"errors":["ReferencesDeletedBackupStorageLocation: backup storage location \"read-only-location\" is read-only"],"phase":"Processed"}}`),
+			),
+		}
+
+		assert.Equal(t, expectedActions, td.client.Actions())
+	})
+
+	t.Run("blocked, backup storage location bucket does not match configured bucket", func(t *testing.T) {
+		backup := arktest.NewTestBackup().WithName("foo").Backup
+		backup.Spec.StorageLocation = "other-bucket-location"
+
+		bsl := &v1.BackupStorageLocation{
+			ObjectMeta: metav1.ObjectMeta{Namespace: backup.Namespace, Name: "other-bucket-location"},
+			Spec:       v1.BackupStorageLocationSpec{Bucket: "some-other-bucket"},
+		}
+
+		td := setupBackupDeletionControllerTest(backup, bsl)
+		td.sharedInformers.Ark().V1().BackupStorageLocations().Informer().GetStore().Add(bsl)
+		defer td.backupService.AssertExpectations(t)
+
+		td.client.PrependReactor("get", "backups", func(action core.Action) (bool, runtime.Object, error) {
+			return true, backup, nil
+		})
+
+		td.client.PrependReactor("patch", "deletebackuprequests", func(action core.Action) (bool, runtime.Object, error) {
+			return true, td.req, nil
+		})
+
+		err := td.controller.processRequest(td.req)
+		require.NoError(t, err)
+
+		expectedActions := []core.Action{
+			core.NewGetAction(
+				v1.SchemeGroupVersion.WithResource("backups"),
+				td.req.Namespace,
+				td.req.Spec.BackupName,
+			),
+			core.NewPatchAction(
+				v1.SchemeGroupVersion.WithResource("deletebackuprequests"),
+				td.req.Namespace,
+				td.req.Name,
+				[]byte(`{"status":{"errors":["ReferencesDeletedBackupStorageLocation: backup storage location \"other-bucket-location\"'s bucket \"some-other-bucket\" does not match the deletion controller's configured bucket \"bucket\""],"phase":"Processed"}}`),
+			),
+		}
+
+		assert.Equal(t, expectedActions, td.client.Actions())
+	})
+
+	t.Run("blocked, backup is still referenced by its schedule", func(t *testing.T) {
+		backup := arktest.NewTestBackup().WithName("foo").Backup
+		backup.Labels = map[string]string{v1.ScheduleNameLabel: "my-schedule"}
+
+		schedule := &v1.Schedule{
+			ObjectMeta: metav1.ObjectMeta{Namespace: backup.Namespace, Name: "my-schedule"},
+			Status:     v1.ScheduleStatus{LastBackupName: "foo"},
+		}
+
+		td := setupBackupDeletionControllerTest(backup, schedule)
+		td.sharedInformers.Ark().V1().Schedules().Informer().GetStore().Add(schedule)
+		defer td.backupService.AssertExpectations(t)
+
+		td.client.PrependReactor("get", "backups", func(action core.Action) (bool, runtime.Object, error) {
+			return true, backup, nil
+		})
+
+		td.client.PrependReactor("patch", "deletebackuprequests", func(action core.Action) (bool, runtime.Object, error) {
+			return true, td.req, nil
+		})
+
+		err := td.controller.processRequest(td.req)
+		require.NoError(t, err)
+
+		expectedActions := []core.Action{
+			core.NewGetAction(
+				v1.SchemeGroupVersion.WithResource("backups"),
+				td.req.Namespace,
+				td.req.Spec.BackupName,
+			),
+			core.NewPatchAction(
+				v1.SchemeGroupVersion.WithResource("deletebackuprequests"),
+				td.req.Namespace,
+				td.req.Name,
+				[]byte(`{"status":{"errors":["BackupInUseByBackupSchedule: backup is still referenced as the most recent backup for schedule \"my-schedule\""],"phase":"Processed"}}`),
+			),
+		}
+
+		assert.Equal(t, expectedActions, td.client.Actions())
+	})
+
+	t.Run("blocked, restore in progress", func(t *testing.T) {
+		backup := arktest.NewTestBackup().WithName("foo").Backup
+		restore := arktest.NewTestRestore(backup.Namespace, "restore-1", v1.RestorePhaseInProgress).WithBackup("foo").Restore
+
+		td := setupBackupDeletionControllerTest(backup, restore)
+		td.sharedInformers.Ark().V1().Restores().Informer().GetStore().Add(restore)
+		defer td.backupService.AssertExpectations(t)
+
+		td.client.PrependReactor("get", "backups", func(action core.Action) (bool, runtime.Object, error) {
+			return true, backup, nil
+		})
+
+		td.client.PrependReactor("patch", "deletebackuprequests", func(action core.Action) (bool, runtime.Object, error) {
+			return true, td.req, nil
+		})
+
+		err := td.controller.processRequest(td.req)
+		require.NoError(t, err)
+
+		expectedActions := []core.Action{
+			core.NewGetAction(
+				v1.SchemeGroupVersion.WithResource("backups"),
+				td.req.Namespace,
+				td.req.Spec.BackupName,
+			),
+			core.NewPatchAction(
+				v1.SchemeGroupVersion.WithResource("deletebackuprequests"),
+				td.req.Namespace,
+				td.req.Name,
+				[]byte(`{"status":{"errors":["BackupInUseByRestoreInProgress: restore \"restore-1\" is currently in progress for this backup"],"phase":"Processed"}}`),
+			),
+		}
+
+		assert.Equal(t, expectedActions, td.client.Actions())
+	})
+
+	t.Run("losing the deletion lock to another replica does not finalize the request", func(t *testing.T) {
+		backup := arktest.NewTestBackup().WithName("foo").Backup
+
+		td := setupBackupDeletionControllerTest(backup)
+		td.controller.deletionLock = &stubDeletionLock{
+			acquireErr: errors.Errorf("deletion lock %q is currently held by %q", "foo-uid", "other-replica"),
+		}
+		defer td.backupService.AssertExpectations(t)
+
+		td.client.PrependReactor("get", "backups", func(action core.Action) (bool, runtime.Object, error) {
+			return true, backup, nil
+		})
+
+		td.client.PrependReactor("patch", "deletebackuprequests", func(action core.Action) (bool, runtime.Object, error) {
+			return true, td.req, nil
+		})
+
+		err := td.controller.processRequest(td.req)
+		assert.EqualError(t, err, `error acquiring deletion lock: deletion lock "foo-uid" is currently held by "other-replica"`)
+
+		expectedActions := []core.Action{
+			core.NewGetAction(
+				v1.SchemeGroupVersion.WithResource("backups"),
+				td.req.Namespace,
+				td.req.Spec.BackupName,
+			),
+			core.NewPatchAction(
+				v1.SchemeGroupVersion.WithResource("deletebackuprequests"),
+				td.req.Namespace,
+				td.req.Name,
+				[]byte(`{"status":{"phase":"InProgress"}}`),
 			),
 		}
 
+		// Losing the lock must not produce a Processed patch -- that would race the replica
+		// that actually holds the lock.
 		assert.Equal(t, expectedActions, td.client.Actions())
 	})
 
@@ -326,7 +574,7 @@ func TestBackupDeletionControllerProcessRequest(t *testing.T) {
 			return true, backup, nil
 		})
 
-		td.backupService.On("DeleteBackupDir", td.controller.bucket, td.req.Spec.BackupName).Return(nil)
+		td.backupService.On("DeleteBackupDir", mock.Anything, td.controller.bucket, td.req.Spec.BackupName).Return(nil)
 
 		err := td.controller.processRequest(td.req)
 		require.NoError(t, err)
@@ -359,6 +607,12 @@ func TestBackupDeletionControllerProcessRequest(t *testing.T) {
 				td.req.Namespace,
 				"restore-2",
 			),
+			core.NewPatchAction(
+				v1.SchemeGroupVersion.WithResource("deletebackuprequests"),
+				td.req.Namespace,
+				td.req.Name,
+				[]byte(`{"status":{"snapshotResults":[{"attempts":1,"persistentVolumeName":"pv-1","phase":"Completed","snapshotID":"snap-1"}]}}`),
+			),
 			core.NewDeleteAction(
 				v1.SchemeGroupVersion.WithResource("backups"),
 				td.req.Namespace,
@@ -394,6 +648,227 @@ func TestBackupDeletionControllerProcessRequest(t *testing.T) {
 		// Make sure snapshot was deleted
 		assert.Equal(t, 0, td.snapshotService.SnapshotsTaken.Len())
 	})
+
+	t.Run("soft delete, tombstones backup without reclaiming it", func(t *testing.T) {
+		backup := arktest.NewTestBackup().WithName("foo").WithSnapshot("pv-1", "snap-1").Backup
+		backup.UID = "uid"
+
+		td := setupBackupDeletionControllerTest(backup)
+		td.req.Spec.Mode = v1.DeleteBackupRequestModeSoft
+		td.controller.deletionRecoveryWindow = time.Hour
+		td.snapshotService.SnapshotsTaken.Insert("snap-1")
+
+		fakeClock := &clock.FakeClock{}
+		fakeClock.SetTime(time.Date(2018, 4, 4, 12, 0, 0, 0, time.UTC))
+		td.controller.clock = fakeClock
+
+		td.client.PrependReactor("get", "backups", func(action core.Action) (bool, runtime.Object, error) {
+			return true, backup, nil
+		})
+
+		td.client.PrependReactor("patch", "deletebackuprequests", func(action core.Action) (bool, runtime.Object, error) {
+			return true, td.req, nil
+		})
+
+		td.client.PrependReactor("patch", "backups", func(action core.Action) (bool, runtime.Object, error) {
+			return true, backup, nil
+		})
+
+		err := td.controller.processRequest(td.req)
+		require.NoError(t, err)
+
+		expectedRecoverableUntil := metav1.NewTime(time.Date(2018, 4, 4, 13, 0, 0, 0, time.UTC))
+		expectedActions := []core.Action{
+			core.NewGetAction(
+				v1.SchemeGroupVersion.WithResource("backups"),
+				td.req.Namespace,
+				td.req.Spec.BackupName,
+			),
+			core.NewPatchAction(
+				v1.SchemeGroupVersion.WithResource("deletebackuprequests"),
+				td.req.Namespace,
+				td.req.Name,
+				[]byte(`{"status":{"phase":"InProgress"}}`),
+			),
+			core.NewPatchAction(
+				v1.SchemeGroupVersion.WithResource("backups"),
+				td.req.Namespace,
+				td.req.Spec.BackupName,
+				[]byte(`{"status":{"phase":"PendingDeletion"}}`),
+			),
+			core.NewPatchAction(
+				v1.SchemeGroupVersion.WithResource("deletebackuprequests"),
+				td.req.Namespace,
+				td.req.Name,
+				[]byte(fmt.Sprintf(`{"status":{"phase":"PendingDeletion","recoverableUntil":%q}}`, expectedRecoverableUntil.Format(time.RFC3339))),
+			),
+		}
+
+		assert.Equal(t, expectedActions, td.client.Actions())
+
+		// Neither snapshot nor backup storage should have been touched yet.
+		assert.Equal(t, 1, td.snapshotService.SnapshotsTaken.Len())
+	})
+
+	t.Run("locked snapshot is marked AccessDenied and the backup is still reclaimed", func(t *testing.T) {
+		backup := arktest.NewTestBackup().WithName("foo").WithSnapshot("pv-1", "snap-1").Backup
+		backup.UID = "uid"
+
+		td := setupBackupDeletionControllerTest(backup)
+		td.controller.snapshotService = &stubSnapshotService{
+			deleteSnapshotError: cloudprovider.ErrSnapshotLocked,
+		}
+		defer td.backupService.AssertExpectations(t)
+
+		td.client.PrependReactor("get", "backups", func(action core.Action) (bool, runtime.Object, error) {
+			return true, backup, nil
+		})
+		td.client.PrependReactor("patch", "deletebackuprequests", func(action core.Action) (bool, runtime.Object, error) {
+			return true, td.req, nil
+		})
+		td.client.PrependReactor("patch", "backups", func(action core.Action) (bool, runtime.Object, error) {
+			return true, backup, nil
+		})
+
+		td.backupService.On("DeleteBackupDir", mock.Anything, td.controller.bucket, td.req.Spec.BackupName).Return(nil)
+
+		err := td.controller.processRequest(td.req)
+		require.NoError(t, err)
+
+		var snapshotResultsPatch, finalPatch core.PatchAction
+		for _, action := range td.client.Actions() {
+			patchAction, ok := action.(core.PatchAction)
+			if !ok || patchAction.GetResource().Resource != "deletebackuprequests" {
+				continue
+			}
+			if strings.Contains(string(patchAction.GetPatch()), "snapshotResults") {
+				snapshotResultsPatch = patchAction
+			}
+			if strings.Contains(string(patchAction.GetPatch()), "Processed") {
+				finalPatch = patchAction
+			}
+		}
+
+		require.NotNil(t, snapshotResultsPatch)
+		assert.Contains(t, string(snapshotResultsPatch.GetPatch()), `"phase":"AccessDenied"`)
+
+		require.NotNil(t, finalPatch)
+		assert.Contains(t, string(finalPatch.GetPatch()), "is locked and was not deleted")
+	})
+
+	t.Run("transient snapshot deletion error is retried rather than finalized", func(t *testing.T) {
+		backup := arktest.NewTestBackup().WithName("foo").WithSnapshot("pv-1", "snap-1").Backup
+		backup.UID = "uid"
+
+		td := setupBackupDeletionControllerTest(backup)
+		td.controller.snapshotService = &stubSnapshotService{
+			deleteSnapshotError: cloudprovider.ErrTransient{Cause: errors.New("throttled")},
+		}
+		// DeleteBackupDir must not be called while a snapshot is still retrying.
+		defer td.backupService.AssertExpectations(t)
+
+		td.client.PrependReactor("get", "backups", func(action core.Action) (bool, runtime.Object, error) {
+			return true, backup, nil
+		})
+		td.client.PrependReactor("patch", "deletebackuprequests", func(action core.Action) (bool, runtime.Object, error) {
+			return true, td.req, nil
+		})
+		td.client.PrependReactor("patch", "backups", func(action core.Action) (bool, runtime.Object, error) {
+			return true, backup, nil
+		})
+
+		err := td.controller.processRequest(td.req)
+		assert.Error(t, err)
+
+		for _, action := range td.client.Actions() {
+			patchAction, ok := action.(core.PatchAction)
+			if !ok || patchAction.GetResource().Resource != "deletebackuprequests" {
+				continue
+			}
+			assert.NotContains(t, string(patchAction.GetPatch()), `"phase":"Processed"`)
+		}
+	})
+
+	t.Run("snapshot already deleted out-of-band is treated as completed, not retried", func(t *testing.T) {
+		backup := arktest.NewTestBackup().WithName("foo").WithSnapshot("pv-1", "snap-1").Backup
+		backup.UID = "uid"
+
+		td := setupBackupDeletionControllerTest(backup)
+		defer td.backupService.AssertExpectations(t)
+
+		// td.snapshotService is the real arktest.FakeSnapshotService used throughout this
+		// suite; "snap-1" is never inserted into SnapshotsTaken, so DeleteSnapshot reports it
+		// as already gone, exercising the same cloudprovider.ErrSnapshotNotFound
+		// classification path deleteSnapshots uses in production.
+
+		td.client.PrependReactor("get", "backups", func(action core.Action) (bool, runtime.Object, error) {
+			return true, backup, nil
+		})
+		td.client.PrependReactor("patch", "deletebackuprequests", func(action core.Action) (bool, runtime.Object, error) {
+			return true, td.req, nil
+		})
+		td.client.PrependReactor("patch", "backups", func(action core.Action) (bool, runtime.Object, error) {
+			return true, backup, nil
+		})
+
+		td.backupService.On("DeleteBackupDir", mock.Anything, td.controller.bucket, td.req.Spec.BackupName).Return(nil)
+
+		err := td.controller.processRequest(td.req)
+		require.NoError(t, err)
+
+		var snapshotResultsPatch, finalPatch core.PatchAction
+		for _, action := range td.client.Actions() {
+			patchAction, ok := action.(core.PatchAction)
+			if !ok || patchAction.GetResource().Resource != "deletebackuprequests" {
+				continue
+			}
+			if strings.Contains(string(patchAction.GetPatch()), "snapshotResults") {
+				snapshotResultsPatch = patchAction
+			}
+			if strings.Contains(string(patchAction.GetPatch()), "Processed") {
+				finalPatch = patchAction
+			}
+		}
+
+		require.NotNil(t, snapshotResultsPatch)
+		assert.Contains(t, string(snapshotResultsPatch.GetPatch()), `"phase":"Completed"`)
+
+		require.NotNil(t, finalPatch)
+		assert.Equal(t, `{"status":{"phase":"Processed"}}`, string(finalPatch.GetPatch()))
+	})
+}
+
+// stubSnapshotService is a minimal cloudprovider.SnapshotService whose DeleteSnapshot always
+// returns deleteSnapshotError, useful for exercising the typed-error retry/AccessDenied paths that
+// arktest.FakeSnapshotService doesn't support.
+type stubSnapshotService struct {
+	deleteSnapshotError error
+}
+
+func (s *stubSnapshotService) CreateSnapshot(volumeID, volumeAZ string, tags map[string]string) (string, error) {
+	return "", nil
+}
+
+func (s *stubSnapshotService) DeleteSnapshot(ctx context.Context, snapshotID string) error {
+	return s.deleteSnapshotError
+}
+
+func (s *stubSnapshotService) GetVolumeID(pv *unstructured.Unstructured) (string, error) {
+	return "", nil
+}
+
+func (s *stubSnapshotService) SetVolumeID(pv *unstructured.Unstructured, volumeID string) (*unstructured.Unstructured, error) {
+	return pv, nil
+}
+
+// stubDeletionLock is a DeletionLock whose Acquire always returns acquireErr, useful for
+// simulating another replica already holding the lock.
+type stubDeletionLock struct {
+	acquireErr error
+}
+
+func (l *stubDeletionLock) Acquire(ctx context.Context, namespace, key string) (context.Context, func(), error) {
+	return nil, nil, l.acquireErr
 }
 
 func TestBackupDeletionControllerDeleteExpiredRequests(t *testing.T) {
@@ -519,6 +994,11 @@ func TestBackupDeletionControllerDeleteExpiredRequests(t *testing.T) {
 				"bucket",
 				sharedInformers.Ark().V1().Restores(),
 				client.ArkV1(), // restoreClient
+				0,              // deletionRecoveryWindow
+				sharedInformers.Ark().V1().BackupStorageLocations(),
+				sharedInformers.Ark().V1().Schedules(),
+				record.NewFakeRecorder(100),
+				kubefake.NewSimpleClientset(),
 			).(*backupDeletionController)
 
 			fakeClock := &clock.FakeClock{}
@@ -541,3 +1021,62 @@ func TestBackupDeletionControllerDeleteExpiredRequests(t *testing.T) {
 
 	}
 }
+
+func TestBackupDeletionControllerReapExpiredTombstones(t *testing.T) {
+	now := time.Date(2018, 4, 4, 12, 0, 0, 0, time.UTC)
+	expired := metav1.NewTime(now.Add(-time.Minute))
+	notExpired := metav1.NewTime(now.Add(time.Minute))
+
+	backup := arktest.NewTestBackup().WithName("foo").WithSnapshot("pv-1", "snap-1").Backup
+	backup.Namespace = "heptio-ark"
+
+	req := &v1.DeleteBackupRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "heptio-ark",
+			Name:      "foo-abcde",
+		},
+		Spec: v1.DeleteBackupRequestSpec{
+			BackupName: "foo",
+			Mode:       v1.DeleteBackupRequestModeSoft,
+		},
+		Status: v1.DeleteBackupRequestStatus{
+			Phase:            v1.DeleteBackupRequestPhasePendingDeletion,
+			RecoverableUntil: &expired,
+		},
+	}
+
+	td := setupBackupDeletionControllerTest(backup, req)
+	td.sharedInformers.Ark().V1().DeleteBackupRequests().Informer().GetStore().Add(req)
+
+	fakeClock := &clock.FakeClock{}
+	fakeClock.SetTime(now)
+	td.controller.clock = fakeClock
+
+	td.client.PrependReactor("get", "backups", func(action core.Action) (bool, runtime.Object, error) {
+		return true, backup, nil
+	})
+	td.client.PrependReactor("patch", "backups", func(action core.Action) (bool, runtime.Object, error) {
+		return true, backup, nil
+	})
+	td.client.PrependReactor("patch", "deletebackuprequests", func(action core.Action) (bool, runtime.Object, error) {
+		return true, req, nil
+	})
+
+	td.backupService.On("DeleteBackupDir", mock.Anything, td.controller.bucket, "foo").Return(nil)
+	td.snapshotService.SnapshotsTaken.Insert("snap-1")
+
+	td.controller.reapExpiredTombstones()
+
+	// The recovery window has elapsed, so the backup's snapshot should now be gone.
+	assert.Equal(t, 0, td.snapshotService.SnapshotsTaken.Len())
+	td.backupService.AssertExpectations(t)
+
+	// A request whose window hasn't elapsed yet shouldn't be touched.
+	req.Status.RecoverableUntil = &notExpired
+	td.sharedInformers.Ark().V1().DeleteBackupRequests().Informer().GetStore().Update(req)
+	td.snapshotService.SnapshotsTaken.Insert("snap-1")
+
+	td.controller.reapExpiredTombstones()
+
+	assert.Equal(t, 1, td.snapshotService.SnapshotsTaken.Len())
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2018 the Heptio Ark contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/heptio/ark/pkg/apis/ark/v1"
+)
+
+// DeletionBlockReason is a structured, machine-readable reason why a DeleteBackupRequest can't
+// currently be processed.
+type DeletionBlockReason string
+
+const (
+	// ReasonReferencesDeletedBackupStorageLocation means the backup's BackupStorageLocation no
+	// longer exists, is read-only, or points at a different bucket than the one the controller
+	// is configured to delete from.
+	ReasonReferencesDeletedBackupStorageLocation DeletionBlockReason = "ReferencesDeletedBackupStorageLocation"
+	// ReasonBackupInUseByBackupSchedule means the backup is still the most recent backup produced
+	// by a live Schedule.
+	ReasonBackupInUseByBackupSchedule DeletionBlockReason = "BackupInUseByBackupSchedule"
+	// ReasonBackupInUseByRestoreInProgress means a Restore that targets the backup is currently
+	// running.
+	ReasonBackupInUseByRestoreInProgress DeletionBlockReason = "BackupInUseByRestoreInProgress"
+)
+
+// validateForDeletion checks whether backup is currently safe to delete. If it isn't, it returns
+// the structured reason and a human-readable message; otherwise blocked is false.
+func (c *backupDeletionController) validateForDeletion(backup *v1.Backup) (reason DeletionBlockReason, message string, blocked bool) {
+	if backup.Spec.StorageLocation != "" {
+		bsl, err := c.bslLister.BackupStorageLocations(backup.Namespace).Get(backup.Spec.StorageLocation)
+		switch {
+		case apierrors.IsNotFound(err):
+			return ReasonReferencesDeletedBackupStorageLocation, fmt.Sprintf("backup storage location %q no longer exists", backup.Spec.StorageLocation), true
+		case err != nil:
+			return ReasonReferencesDeletedBackupStorageLocation, fmt.Sprintf("error getting backup storage location %q: %v", backup.Spec.StorageLocation, err), true
+		case bsl.Spec.AccessMode == v1.BackupStorageLocationAccessModeReadOnly:
+			return ReasonReferencesDeletedBackupStorageLocation, fmt.Sprintf("backup storage location %q is read-only", backup.Spec.StorageLocation), true
+		case bsl.Spec.Bucket != "" && bsl.Spec.Bucket != c.bucket:
+			return ReasonReferencesDeletedBackupStorageLocation, fmt.Sprintf("backup storage location %q's bucket %q does not match the deletion controller's configured bucket %q", backup.Spec.StorageLocation, bsl.Spec.Bucket, c.bucket), true
+		}
+	}
+
+	if scheduleName, ok := backup.Labels[v1.ScheduleNameLabel]; ok {
+		schedule, err := c.scheduleLister.Schedules(backup.Namespace).Get(scheduleName)
+		if err == nil && schedule.Status.LastBackupName == backup.Name {
+			return ReasonBackupInUseByBackupSchedule, fmt.Sprintf("backup is still referenced as the most recent backup for schedule %q", scheduleName), true
+		}
+	}
+
+	restores, err := c.restoreLister.Restores(backup.Namespace).List(labels.Everything())
+	if err == nil {
+		for _, restore := range restores {
+			if restore.Spec.BackupName == backup.Name && restore.Status.Phase == v1.RestorePhaseInProgress {
+				return ReasonBackupInUseByRestoreInProgress, fmt.Sprintf("restore %q is currently in progress for this backup", restore.Name), true
+			}
+		}
+	}
+
+	return "", "", false
+}
+
+// recordDeletionBlockedEvent emits a Kubernetes Event on backup recording why its deletion was
+// refused, so operators can diagnose the situation without reading controller logs.
+func (c *backupDeletionController) recordDeletionBlockedEvent(backup *v1.Backup, reason DeletionBlockReason, message string) {
+	if c.eventRecorder == nil {
+		return
+	}
+	c.eventRecorder.Event(backup, corev1.EventTypeWarning, string(reason), message)
+}
@@ -0,0 +1,274 @@
+/*
+Copyright 2018 the Heptio Ark contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/heptio/ark/pkg/apis/ark/v1"
+	arkv1client "github.com/heptio/ark/pkg/generated/clientset/versioned/typed/ark/v1"
+	informers "github.com/heptio/ark/pkg/generated/informers/externalversions/ark/v1"
+	listers "github.com/heptio/ark/pkg/generated/listers/ark/v1"
+)
+
+// defaultBackupDeletionsConcurrency bounds how many targets within a single DeleteBackupsRequest
+// are reclaimed at once, so that expiring hundreds of backups from a retention policy sweep
+// doesn't overwhelm the object storage provider or the snapshot API.
+const defaultBackupDeletionsConcurrency = 10
+
+// backupDeletionsController processes DeleteBackupsRequests, which delete a batch of backups as a
+// single unit. It delegates the validation, locking, and reclaiming of each individual backup to a
+// backupDeletionController, so that a backup behaves identically whether it's deleted on its own
+// or as part of a batch.
+type backupDeletionsController struct {
+	*genericController
+
+	deleteBackupsRequestClient arkv1client.DeleteBackupsRequestsGetter
+	deleteBackupsRequestLister listers.DeleteBackupsRequestLister
+	backupClient               arkv1client.BackupsGetter
+	backupDeletion             *backupDeletionController
+
+	processRequestFunc func(*v1.DeleteBackupsRequest) error
+	concurrency        int
+}
+
+// NewBackupDeletionsController creates a new backupDeletionsController. backupDeletion is used to
+// validate, lock, and reclaim each target backup, so it should be the same controller instance
+// used to process individual DeleteBackupRequests.
+func NewBackupDeletionsController(
+	logger logrus.FieldLogger,
+	deleteBackupsRequestInformer informers.DeleteBackupsRequestInformer,
+	deleteBackupsRequestClient arkv1client.DeleteBackupsRequestsGetter,
+	backupClient arkv1client.BackupsGetter,
+	backupDeletion *backupDeletionController,
+) Interface {
+	c := &backupDeletionsController{
+		genericController:          newGenericController("backup-deletions", logger),
+		deleteBackupsRequestClient: deleteBackupsRequestClient,
+		deleteBackupsRequestLister: deleteBackupsRequestInformer.Lister(),
+		backupClient:               backupClient,
+		backupDeletion:             backupDeletion,
+		concurrency:                defaultBackupDeletionsConcurrency,
+	}
+
+	c.syncHandler = c.processQueueItem
+	c.processRequestFunc = c.processRequest
+	c.cacheSyncWaiters = append(c.cacheSyncWaiters, deleteBackupsRequestInformer.Informer().HasSynced)
+
+	deleteBackupsRequestInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: c.enqueue,
+		},
+	)
+
+	return c
+}
+
+func (c *backupDeletionsController) processQueueItem(key string) error {
+	ns, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return errors.Wrap(err, "error splitting queue key")
+	}
+
+	log := c.logger.WithField("key", key)
+
+	req, err := c.deleteBackupsRequestLister.DeleteBackupsRequests(ns).Get(name)
+	if apierrors.IsNotFound(err) {
+		log.Debug("Unable to find DeleteBackupsRequest")
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "error getting DeleteBackupsRequest")
+	}
+
+	if req.Status.Phase == v1.DeleteBackupsRequestPhaseProcessed {
+		log.Debug("DeleteBackupsRequest has already been processed, skipping")
+		return nil
+	}
+
+	return c.processRequestFunc(req)
+}
+
+// processRequest patches req to InProgress, reclaims every target backup with bounded
+// parallelism, and patches req to Processed with the aggregated per-backup results.
+func (c *backupDeletionsController) processRequest(req *v1.DeleteBackupsRequest) error {
+	log := c.logger.WithFields(logrus.Fields{
+		"namespace": req.Namespace,
+		"name":      req.Name,
+	})
+
+	req, err := patchDeleteBackupsRequest(req, c.deleteBackupsRequestClient, func(r *v1.DeleteBackupsRequest) {
+		r.Status.Phase = v1.DeleteBackupsRequestPhaseInProgress
+	})
+	if err != nil {
+		return errors.Wrap(err, "error patching DeleteBackupsRequest")
+	}
+
+	results := c.deleteTargets(log, req.Namespace, req.Spec.Targets)
+
+	if req.Spec.Quiet {
+		results = quietResults(results)
+	}
+
+	if _, err := patchDeleteBackupsRequest(req, c.deleteBackupsRequestClient, func(r *v1.DeleteBackupsRequest) {
+		r.Status.Phase = v1.DeleteBackupsRequestPhaseProcessed
+		r.Status.Results = results
+	}); err != nil {
+		log.WithError(err).Error("Error patching DeleteBackupsRequest's final status")
+	}
+
+	return nil
+}
+
+// deleteTargets reclaims each of targets concurrently, bounded by c.concurrency, and returns one
+// BackupDeleteResult per target, in the same order as targets.
+func (c *backupDeletionsController) deleteTargets(log logrus.FieldLogger, namespace string, targets []v1.BackupDeleteTarget) []v1.BackupDeleteResult {
+	results := make([]v1.BackupDeleteResult, len(targets))
+
+	sem := make(chan struct{}, c.concurrency)
+	var wg sync.WaitGroup
+
+	for i, target := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int, target v1.BackupDeleteTarget) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			results[i] = c.deleteTarget(log, namespace, target)
+		}(i, target)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// deleteTarget deletes a single backup, reusing the same validation, locking, and reclaim logic
+// that the single-backup deletion path uses.
+func (c *backupDeletionsController) deleteTarget(log logrus.FieldLogger, namespace string, target v1.BackupDeleteTarget) v1.BackupDeleteResult {
+	result := v1.BackupDeleteResult{Key: target.BackupName, VersionID: target.UID}
+
+	backup, err := c.backupClient.Backups(namespace).Get(target.BackupName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		result.Error = "backup not found"
+		return result
+	}
+	if err != nil {
+		result.Error = fmt.Sprintf("error getting backup: %v", err)
+		return result
+	}
+
+	if len(backup.Status.VolumeBackups) > 0 && c.backupDeletion.snapshotService == nil {
+		result.Error = "unable to delete backup because it includes PV snapshots and Ark is not configured with a PersistentVolumeProvider"
+		return result
+	}
+
+	if reason, message, blocked := c.backupDeletion.validateForDeletion(backup); blocked {
+		c.backupDeletion.recordDeletionBlockedEvent(backup, reason, message)
+		result.Error = fmt.Sprintf("%s: %s", reason, message)
+		return result
+	}
+
+	// syntheticReq carries just enough of a DeleteBackupRequest's shape to reuse the deletion
+	// lock's key derivation and reclaimBackup's namespace/backup-name lookups; it's never
+	// persisted to the API.
+	syntheticReq := &v1.DeleteBackupRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Labels:    map[string]string{v1.BackupUIDLabel: target.UID},
+		},
+		Spec: v1.DeleteBackupRequestSpec{BackupName: target.BackupName},
+	}
+
+	// Losing the race for the deletion lock -- most commonly because another replica, or another
+	// target in this same batch naming the same backup twice, already holds it -- isn't a failure
+	// this batch can retry: a DeleteBackupsRequest is never revisited once Processed. Report it as
+	// unresolved rather than implying a later pass will pick it back up.
+	lockCtx, release, err := c.backupDeletion.acquireDeletionLock(syntheticReq)
+	if err != nil {
+		result.Error = fmt.Sprintf("unable to acquire deletion lock, not retried as part of this batch: %v", err)
+		return result
+	}
+	defer release()
+
+	if errs, retryable := c.backupDeletion.reclaimBackup(lockCtx, log, syntheticReq, backup); len(errs) > 0 {
+		if retryable {
+			// Unlike a single DeleteBackupRequest, a DeleteBackupsRequest is never revisited once
+			// Processed, so a transient snapshot error here won't actually be retried -- say so
+			// plainly instead of promising a retry that will never happen.
+			result.Error = fmt.Sprintf("not retried as part of this batch: %s", strings.Join(errs, "; "))
+		} else {
+			result.Error = strings.Join(errs, "; ")
+		}
+	}
+
+	return result
+}
+
+// quietResults drops successful entries, leaving only the ones that encountered an error --
+// mirroring the Quiet mode of S3's DeleteObjects API.
+func quietResults(results []v1.BackupDeleteResult) []v1.BackupDeleteResult {
+	quieted := make([]v1.BackupDeleteResult, 0, len(results))
+	for _, result := range results {
+		if result.Error != "" {
+			quieted = append(quieted, result)
+		}
+	}
+	return quieted
+}
+
+// patchDeleteBackupsRequest mutates req via the mutate func, computes a JSON merge patch between
+// the original and mutated objects, and applies it via the API, returning the patched object.
+func patchDeleteBackupsRequest(req *v1.DeleteBackupsRequest, client arkv1client.DeleteBackupsRequestsGetter, mutate func(*v1.DeleteBackupsRequest)) (*v1.DeleteBackupsRequest, error) {
+	before, err := json.Marshal(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshalling original DeleteBackupsRequest")
+	}
+
+	updated := req.DeepCopy()
+	mutate(updated)
+
+	after, err := json.Marshal(updated)
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshalling updated DeleteBackupsRequest")
+	}
+
+	patchBytes, err := jsonpatch.CreateMergePatch(before, after)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating merge patch")
+	}
+
+	patched, err := client.DeleteBackupsRequests(req.Namespace).Patch(req.Name, types.MergePatchType, patchBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return patched, nil
+}
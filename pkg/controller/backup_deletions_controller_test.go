@@ -0,0 +1,138 @@
+/*
+Copyright 2018 the Heptio Ark contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/heptio/ark/pkg/apis/ark/v1"
+	arktest "github.com/heptio/ark/pkg/util/test"
+)
+
+func setupBackupDeletionsControllerTest() (*backupDeletionsController, *backupDeletionControllerTestData) {
+	single := setupBackupDeletionControllerTest()
+
+	batch := &backupDeletionsController{
+		deleteBackupsRequestClient: single.client.ArkV1(),
+		backupClient:               single.client.ArkV1(),
+		backupDeletion:             single.controller,
+		concurrency:                defaultBackupDeletionsConcurrency,
+	}
+
+	return batch, single
+}
+
+func TestBackupDeletionsControllerDeleteTargets(t *testing.T) {
+	t.Run("backup not found", func(t *testing.T) {
+		batch, td := setupBackupDeletionsControllerTest()
+		defer td.backupService.AssertExpectations(t)
+
+		targets := []v1.BackupDeleteTarget{{BackupName: "does-not-exist"}}
+
+		results := batch.deleteTargets(arktest.NewLogger(), "heptio-ark", targets)
+
+		require.Len(t, results, 1)
+		assert.Equal(t, "does-not-exist", results[0].Key)
+		assert.Equal(t, "backup not found", results[0].Error)
+	})
+
+	t.Run("backup has snapshots and no snapshot service is configured", func(t *testing.T) {
+		batch, td := setupBackupDeletionsControllerTest()
+		td.controller.snapshotService = nil
+		defer td.backupService.AssertExpectations(t)
+
+		backup := arktest.NewTestBackup().WithName("backup-1").WithSnapshot("pv-1", "snap-1").Backup
+		_, err := td.client.ArkV1().Backups(backup.Namespace).Create(backup)
+		require.NoError(t, err)
+
+		targets := []v1.BackupDeleteTarget{{BackupName: backup.Name}}
+
+		results := batch.deleteTargets(arktest.NewLogger(), backup.Namespace, targets)
+
+		require.Len(t, results, 1)
+		assert.Equal(t, "unable to delete backup because it includes PV snapshots and Ark is not configured with a PersistentVolumeProvider", results[0].Error)
+	})
+
+	t.Run("backup with a volume snapshot is reclaimed without error", func(t *testing.T) {
+		batch, td := setupBackupDeletionsControllerTest()
+		td.backupService.On("DeleteBackupDir", mock.Anything, "bucket", "backup-1").Return(nil)
+		defer td.backupService.AssertExpectations(t)
+
+		backup := arktest.NewTestBackup().WithName("backup-1").WithSnapshot("pv-1", "snap-1").Backup
+		_, err := td.client.ArkV1().Backups(backup.Namespace).Create(backup)
+		require.NoError(t, err)
+		td.snapshotService.SnapshotsTaken.Insert("snap-1")
+
+		targets := []v1.BackupDeleteTarget{{BackupName: backup.Name}}
+
+		results := batch.deleteTargets(arktest.NewLogger(), backup.Namespace, targets)
+
+		require.Len(t, results, 1)
+		assert.Empty(t, results[0].Error)
+		assert.Equal(t, 0, td.snapshotService.SnapshotsTaken.Len())
+	})
+
+	t.Run("multiple targets are each reclaimed and return results in order", func(t *testing.T) {
+		batch, td := setupBackupDeletionsControllerTest()
+		td.backupService.On("DeleteBackupDir", mock.Anything, "bucket", "backup-1").Return(nil)
+		td.backupService.On("DeleteBackupDir", mock.Anything, "bucket", "backup-2").Return(nil)
+		defer td.backupService.AssertExpectations(t)
+
+		backup1 := arktest.NewTestBackup().WithName("backup-1").Backup
+		backup2 := arktest.NewTestBackup().WithName("backup-2").Backup
+		_, err := td.client.ArkV1().Backups(backup1.Namespace).Create(backup1)
+		require.NoError(t, err)
+		_, err = td.client.ArkV1().Backups(backup2.Namespace).Create(backup2)
+		require.NoError(t, err)
+
+		targets := []v1.BackupDeleteTarget{
+			{BackupName: "backup-1"},
+			{BackupName: "backup-2"},
+		}
+
+		results := batch.deleteTargets(arktest.NewLogger(), backup1.Namespace, targets)
+
+		require.Len(t, results, 2)
+		assert.Equal(t, "backup-1", results[0].Key)
+		assert.Empty(t, results[0].Error)
+		assert.Equal(t, "backup-2", results[1].Key)
+		assert.Empty(t, results[1].Error)
+	})
+}
+
+func TestQuietResults(t *testing.T) {
+	results := []v1.BackupDeleteResult{
+		{Key: "backup-1"},
+		{Key: "backup-2", Error: "boom"},
+		{Key: "backup-3"},
+	}
+
+	quieted := quietResults(results)
+
+	keys := make([]string, 0, len(quieted))
+	for _, result := range quieted {
+		keys = append(keys, result.Key)
+	}
+	sort.Strings(keys)
+
+	assert.Equal(t, []string{"backup-2"}, keys)
+}
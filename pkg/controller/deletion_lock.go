@@ -0,0 +1,200 @@
+/*
+Copyright 2018 the Heptio Ark contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1api "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	deletionLockHolderAnnotation    = "ark.heptio.com/deletion-lock-holder"
+	deletionLockRenewTimeAnnotation = "ark.heptio.com/deletion-lock-renew-time"
+
+	deletionLockLeaseDuration = 2 * time.Minute
+	deletionLockRefreshPeriod = 30 * time.Second
+)
+
+// DeletionLock coordinates exclusive ownership of a single backup's deletion across multiple
+// BackupDeletionController replicas, so that only one replica at a time reclaims a given backup's
+// object storage and snapshots.
+type DeletionLock interface {
+	// Acquire takes exclusive ownership of key within namespace and starts a background goroutine
+	// that periodically renews the lock. If a renewal ever fails -- because another replica has
+	// taken over, or the API server is unreachable -- lockCtx is cancelled, so that any long-running
+	// operation using it stops mutating storage on behalf of a request this replica no longer owns.
+	// The caller must always invoke release, which stops the refresh goroutine, cancels lockCtx, and
+	// relinquishes the lock.
+	Acquire(ctx context.Context, namespace, key string) (lockCtx context.Context, release func(), err error)
+}
+
+// configMapDeletionLock implements DeletionLock using a per-key ConfigMap as a distributed lock,
+// in the same spirit as a coordination.k8s.io/Lease.
+type configMapDeletionLock struct {
+	kubeClient     kubernetes.Interface
+	holderIdentity string
+	leaseDuration  time.Duration
+	refreshPeriod  time.Duration
+	clock          clock.Clock
+}
+
+// NewConfigMapDeletionLock creates a DeletionLock backed by ConfigMaps. holderIdentity should be
+// unique per controller replica; it's recorded in the lock's annotations so operators can tell
+// which replica currently owns an in-progress deletion.
+func NewConfigMapDeletionLock(kubeClient kubernetes.Interface, holderIdentity string) DeletionLock {
+	return &configMapDeletionLock{
+		kubeClient:     kubeClient,
+		holderIdentity: holderIdentity,
+		leaseDuration:  deletionLockLeaseDuration,
+		refreshPeriod:  deletionLockRefreshPeriod,
+		clock:          clock.RealClock{},
+	}
+}
+
+// defaultHolderIdentity returns the local hostname, falling back to a generic identifier if the
+// hostname can't be determined.
+func defaultHolderIdentity() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return "ark-backup-deletion-controller"
+	}
+	return hostname
+}
+
+func (l *configMapDeletionLock) lockName(key string) string {
+	return fmt.Sprintf("%s-deletion-lock", key)
+}
+
+func (l *configMapDeletionLock) Acquire(ctx context.Context, namespace, key string) (context.Context, func(), error) {
+	configMaps := l.kubeClient.CoreV1().ConfigMaps(namespace)
+	name := l.lockName(key)
+	now := l.clock.Now()
+
+	cm, err := configMaps.Get(name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		cm = &corev1api.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+				Annotations: map[string]string{
+					deletionLockHolderAnnotation:    l.holderIdentity,
+					deletionLockRenewTimeAnnotation: now.Format(time.RFC3339),
+				},
+			},
+		}
+		if cm, err = configMaps.Create(cm); err != nil {
+			return nil, nil, errors.Wrap(err, "error creating deletion lock")
+		}
+	case err != nil:
+		return nil, nil, errors.Wrap(err, "error getting deletion lock")
+	default:
+		if held, holder := l.isHeldByOther(cm, now); held {
+			return nil, nil, errors.Errorf("deletion lock %q is currently held by %q", name, holder)
+		}
+
+		updated := cm.DeepCopy()
+		updated.Annotations[deletionLockHolderAnnotation] = l.holderIdentity
+		updated.Annotations[deletionLockRenewTimeAnnotation] = now.Format(time.RFC3339)
+
+		if cm, err = configMaps.Update(updated); err != nil {
+			return nil, nil, errors.Wrap(err, "error taking over deletion lock")
+		}
+	}
+
+	lockCtx, cancel := context.WithCancel(ctx)
+	stop := make(chan struct{})
+
+	go l.refresh(configMaps, name, stop, cancel)
+
+	release := func() {
+		close(stop)
+		cancel()
+
+		// Another replica may have taken over this lock (e.g. because this replica's lease
+		// expired while it was still reclaiming the backup) by the time we get here. Only
+		// delete the ConfigMap if we're still the recorded holder, so we don't delete out
+		// from under whoever owns it now and let a third replica race in.
+		current, err := configMaps.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return
+		}
+		if current.Annotations[deletionLockHolderAnnotation] != l.holderIdentity {
+			return
+		}
+
+		_ = configMaps.Delete(name, &metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{UID: &current.UID},
+		})
+	}
+
+	return lockCtx, release, nil
+}
+
+// isHeldByOther reports whether cm represents an unexpired lock held by a different holder.
+func (l *configMapDeletionLock) isHeldByOther(cm *corev1api.ConfigMap, now time.Time) (bool, string) {
+	holder := cm.Annotations[deletionLockHolderAnnotation]
+	if holder == l.holderIdentity {
+		return false, ""
+	}
+
+	renewTime, err := time.Parse(time.RFC3339, cm.Annotations[deletionLockRenewTimeAnnotation])
+	if err != nil || now.Sub(renewTime) >= l.leaseDuration {
+		return false, ""
+	}
+
+	return true, holder
+}
+
+// refresh periodically renews the lock's ConfigMap until told to stop. If a renewal is ever lost
+// -- because another replica has taken over, the ConfigMap was deleted, or the API server can't be
+// reached -- it cancels cancel and returns, leaving the lock to whoever holds it now.
+func (l *configMapDeletionLock) refresh(configMaps corev1client.ConfigMapInterface, name string, stop <-chan struct{}, cancel context.CancelFunc) {
+	ticker := time.NewTicker(l.refreshPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			cm, err := configMaps.Get(name, metav1.GetOptions{})
+			if err != nil || cm.Annotations[deletionLockHolderAnnotation] != l.holderIdentity {
+				cancel()
+				return
+			}
+
+			updated := cm.DeepCopy()
+			updated.Annotations[deletionLockRenewTimeAnnotation] = l.clock.Now().Format(time.RFC3339)
+
+			if _, err := configMaps.Update(updated); err != nil {
+				cancel()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2018 the Heptio Ark contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1api "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestConfigMapDeletionLockAcquire(t *testing.T) {
+	t.Run("no existing lock creates one", func(t *testing.T) {
+		kubeClient := kubefake.NewSimpleClientset()
+		lock := NewConfigMapDeletionLock(kubeClient, "holder-1").(*configMapDeletionLock)
+
+		lockCtx, release, err := lock.Acquire(context.Background(), "heptio-ark", "backup-1-uid")
+		require.NoError(t, err)
+		defer release()
+
+		assert.NoError(t, lockCtx.Err())
+
+		cm, err := kubeClient.CoreV1().ConfigMaps("heptio-ark").Get("backup-1-uid-deletion-lock", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "holder-1", cm.Annotations[deletionLockHolderAnnotation])
+	})
+
+	t.Run("held by another replica within lease duration is refused", func(t *testing.T) {
+		kubeClient := kubefake.NewSimpleClientset()
+		fakeClock := &clock.FakeClock{}
+		fakeClock.SetTime(time.Date(2018, 4, 4, 12, 0, 0, 0, time.UTC))
+
+		lock := NewConfigMapDeletionLock(kubeClient, "holder-1").(*configMapDeletionLock)
+		lock.clock = fakeClock
+
+		_, release, err := lock.Acquire(context.Background(), "heptio-ark", "backup-1-uid")
+		require.NoError(t, err)
+		defer release()
+
+		other := NewConfigMapDeletionLock(kubeClient, "holder-2").(*configMapDeletionLock)
+		other.clock = fakeClock
+
+		_, _, err = other.Acquire(context.Background(), "heptio-ark", "backup-1-uid")
+		assert.Error(t, err)
+	})
+
+	t.Run("expired lock can be taken over by another replica", func(t *testing.T) {
+		kubeClient := kubefake.NewSimpleClientset()
+		fakeClock := &clock.FakeClock{}
+		fakeClock.SetTime(time.Date(2018, 4, 4, 12, 0, 0, 0, time.UTC))
+
+		other := NewConfigMapDeletionLock(kubeClient, "holder-2").(*configMapDeletionLock)
+		other.clock = fakeClock
+
+		// Simulate a lock left behind by a holder that crashed without releasing it, whose
+		// renewal is now well past the lease duration.
+		staleRenewTime := fakeClock.Now().Add(-(other.leaseDuration + time.Minute))
+		_, err := kubeClient.CoreV1().ConfigMaps("heptio-ark").Create(&corev1api.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "heptio-ark",
+				Name:      "backup-1-uid-deletion-lock",
+				Annotations: map[string]string{
+					deletionLockHolderAnnotation:    "holder-1",
+					deletionLockRenewTimeAnnotation: staleRenewTime.Format(time.RFC3339),
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		lockCtx, release, err := other.Acquire(context.Background(), "heptio-ark", "backup-1-uid")
+		require.NoError(t, err)
+		defer release()
+		assert.NoError(t, lockCtx.Err())
+
+		cm, err := kubeClient.CoreV1().ConfigMaps("heptio-ark").Get("backup-1-uid-deletion-lock", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "holder-2", cm.Annotations[deletionLockHolderAnnotation])
+	})
+
+	t.Run("release does not delete a lock that another replica has legitimately taken over", func(t *testing.T) {
+		kubeClient := kubefake.NewSimpleClientset()
+		fakeClock := &clock.FakeClock{}
+		fakeClock.SetTime(time.Date(2018, 4, 4, 12, 0, 0, 0, time.UTC))
+
+		holder := NewConfigMapDeletionLock(kubeClient, "holder-1").(*configMapDeletionLock)
+		holder.clock = fakeClock
+
+		_, release, err := holder.Acquire(context.Background(), "heptio-ark", "backup-1-uid")
+		require.NoError(t, err)
+
+		// Simulate holder-1's lease expiring and holder-2 legitimately taking over while
+		// holder-1 is still in the middle of reclaiming the backup.
+		fakeClock.SetTime(fakeClock.Now().Add(holder.leaseDuration + time.Minute))
+
+		other := NewConfigMapDeletionLock(kubeClient, "holder-2").(*configMapDeletionLock)
+		other.clock = fakeClock
+
+		_, otherRelease, err := other.Acquire(context.Background(), "heptio-ark", "backup-1-uid")
+		require.NoError(t, err)
+		defer otherRelease()
+
+		// holder-1's deferred release, called after it loses ownership, must not delete
+		// holder-2's lock out from under it.
+		release()
+
+		cm, err := kubeClient.CoreV1().ConfigMaps("heptio-ark").Get("backup-1-uid-deletion-lock", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "holder-2", cm.Annotations[deletionLockHolderAnnotation])
+	})
+
+	t.Run("release deletes the lock and cancels the context", func(t *testing.T) {
+		kubeClient := kubefake.NewSimpleClientset()
+		lock := NewConfigMapDeletionLock(kubeClient, "holder-1").(*configMapDeletionLock)
+
+		lockCtx, release, err := lock.Acquire(context.Background(), "heptio-ark", "backup-1-uid")
+		require.NoError(t, err)
+
+		release()
+
+		assert.Error(t, lockCtx.Err())
+
+		_, err = kubeClient.CoreV1().ConfigMaps("heptio-ark").Get("backup-1-uid-deletion-lock", metav1.GetOptions{})
+		assert.Error(t, err)
+	})
+}
+
+func TestConfigMapDeletionLockRefreshCancelsContextWhenLockIsLost(t *testing.T) {
+	kubeClient := kubefake.NewSimpleClientset()
+	lock := NewConfigMapDeletionLock(kubeClient, "holder-1").(*configMapDeletionLock)
+	lock.refreshPeriod = 10 * time.Millisecond
+
+	lockCtx, release, err := lock.Acquire(context.Background(), "heptio-ark", "backup-1-uid")
+	require.NoError(t, err)
+	defer release()
+
+	// Simulate the lock's ConfigMap being deleted out from under us.
+	require.NoError(t, kubeClient.CoreV1().ConfigMaps("heptio-ark").Delete("backup-1-uid-deletion-lock", nil))
+
+	select {
+	case <-lockCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected lock context to be cancelled after the lock was lost")
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2018 the Heptio Ark contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Interface is implemented by all Ark controllers.
+type Interface interface {
+	Run(ctx context.Context, numWorkers int) error
+}
+
+// genericController holds the common behavior shared by all of Ark's
+// controllers: a rate-limited work queue fed by informer event handlers,
+// and a worker pool that drains it by key.
+type genericController struct {
+	name             string
+	queue            workqueue.RateLimitingInterface
+	logger           logrus.FieldLogger
+	syncHandler      func(key string) error
+	resyncFunc       func()
+	resyncPeriod     time.Duration
+	cacheSyncWaiters []cache.InformerSynced
+}
+
+func newGenericController(name string, logger logrus.FieldLogger) *genericController {
+	return &genericController{
+		name:   name,
+		queue:  workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		logger: logger.WithField("controller", name),
+	}
+}
+
+// Run starts the controller's worker goroutines and blocks until ctx is
+// cancelled. If resyncFunc is set, it is invoked every resyncPeriod for as
+// long as the controller is running.
+func (c *genericController) Run(ctx context.Context, numWorkers int) error {
+	var wg sync.WaitGroup
+
+	defer func() {
+		c.logger.Info("Waiting for workers to finish their work")
+
+		c.queue.ShutDown()
+
+		wg.Wait()
+
+		c.logger.Info("All workers have finished")
+	}()
+
+	c.logger.Info("Starting controller")
+	defer c.logger.Info("Shutting down controller")
+
+	c.logger.Info("Waiting for caches to sync")
+	if !cache.WaitForCacheSync(ctx.Done(), c.cacheSyncWaiters...) {
+		return errors.New("timed out waiting for caches to sync")
+	}
+	c.logger.Info("Caches are synced")
+
+	wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		go func() {
+			wait.Until(c.runWorker, time.Second, ctx.Done())
+			wg.Done()
+		}()
+	}
+
+	if c.resyncFunc != nil {
+		wg.Add(1)
+		go func() {
+			wait.Until(c.resyncFunc, c.resyncPeriod, ctx.Done())
+			wg.Done()
+		}()
+	}
+
+	<-ctx.Done()
+
+	return nil
+}
+
+func (c *genericController) runWorker() {
+	// continually take items off the queue (waiting while it's empty) until it's shut down, since
+	// processNextWorkItem only reports false once the queue has been shut down.
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *genericController) processNextWorkItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	err := c.syncHandler(key.(string))
+	c.handleErr(err, key)
+
+	return true
+}
+
+func (c *genericController) handleErr(err error, key interface{}) {
+	if err == nil {
+		c.queue.Forget(key)
+		return
+	}
+
+	c.logger.WithError(err).WithField("key", key).Error("Error in syncHandler, re-adding item to queue")
+	c.queue.AddRateLimited(key)
+}
+
+func (c *genericController) enqueue(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		c.logger.WithError(err).WithField("key", key).Error("Error creating queue key, item not added to queue")
+		return
+	}
+	c.queue.Add(key)
+}
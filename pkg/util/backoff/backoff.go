@@ -0,0 +1,64 @@
+/*
+Copyright 2018 the Heptio Ark contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backoff computes retry delays for controllers that retry operations against external
+// providers, so that a run of transient failures doesn't hammer the provider on every reconcile.
+package backoff
+
+import (
+	"math/rand"
+	"time"
+)
+
+// DefaultBaseDelay and DefaultMaxDelay bound the exponential backoff used by controllers that
+// don't need their own retry schedule.
+const (
+	DefaultBaseDelay = 10 * time.Second
+	DefaultMaxDelay  = 10 * time.Minute
+)
+
+// Backoff computes exponential retry delays with jitter.
+type Backoff struct {
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// NewDefault returns a Backoff using DefaultBaseDelay and DefaultMaxDelay.
+func NewDefault() Backoff {
+	return Backoff{BaseDelay: DefaultBaseDelay, MaxDelay: DefaultMaxDelay}
+}
+
+// Next returns how long to wait before the given attempt. Next(1) is the delay before the first
+// retry, i.e. after the 1st attempt has failed. The delay doubles with each subsequent attempt, is
+// capped at MaxDelay, and has up to 20% jitter added so that many objects retrying at once don't
+// all hit the provider in the same instant.
+func (b Backoff) Next(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	delay := b.BaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= b.MaxDelay {
+			delay = b.MaxDelay
+			break
+		}
+	}
+
+	jitter := time.Duration(rand.Int63n(int64(delay)/5 + 1))
+	return delay + jitter
+}
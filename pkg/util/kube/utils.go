@@ -0,0 +1,30 @@
+/*
+Copyright 2017 the Heptio Ark contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NamespaceAndName returns a string in the format <namespace>/<name>, or just <name> if the
+// namespace is empty.
+func NamespaceAndName(objMeta metav1.Object) string {
+	if objMeta.GetNamespace() == "" {
+		return objMeta.GetName()
+	}
+	return objMeta.GetNamespace() + "/" + objMeta.GetName()
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2017 the Heptio Ark contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/heptio/ark/pkg/apis/ark/v1"
+)
+
+// TestBackup builds a v1.Backup for use in tests.
+type TestBackup struct {
+	Backup *v1.Backup
+}
+
+// NewTestBackup returns a TestBackup with default values.
+func NewTestBackup() *TestBackup {
+	return &TestBackup{
+		Backup: &v1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "heptio-ark",
+			},
+		},
+	}
+}
+
+func (b *TestBackup) WithName(name string) *TestBackup {
+	b.Backup.Name = name
+	return b
+}
+
+func (b *TestBackup) WithNamespace(namespace string) *TestBackup {
+	b.Backup.Namespace = namespace
+	return b
+}
+
+func (b *TestBackup) WithPhase(phase v1.BackupPhase) *TestBackup {
+	b.Backup.Status.Phase = phase
+	return b
+}
+
+// WithSnapshot records a volume backup for the given persistent volume/snapshot ID pair.
+func (b *TestBackup) WithSnapshot(pvName, snapshotID string) *TestBackup {
+	if b.Backup.Status.VolumeBackups == nil {
+		b.Backup.Status.VolumeBackups = make(map[string]*v1.VolumeBackupInfo)
+	}
+	b.Backup.Status.VolumeBackups[pvName] = &v1.VolumeBackupInfo{SnapshotID: snapshotID}
+	return b
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2017 the Heptio Ark contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"io"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/heptio/ark/pkg/apis/ark/v1"
+)
+
+// BackupService is a mock implementation of backup.Service.
+type BackupService struct {
+	mock.Mock
+}
+
+func (s *BackupService) UploadBackup(bucket, backupName string, metadata, backupContents, log io.Reader) error {
+	args := s.Called(bucket, backupName, metadata, backupContents, log)
+	return args.Error(0)
+}
+
+func (s *BackupService) DownloadBackup(bucket, backupName string) (io.ReadCloser, error) {
+	args := s.Called(bucket, backupName)
+	var rc io.ReadCloser
+	if args.Get(0) != nil {
+		rc = args.Get(0).(io.ReadCloser)
+	}
+	return rc, args.Error(1)
+}
+
+func (s *BackupService) DeleteBackupDir(ctx context.Context, bucket, backupName string) error {
+	args := s.Called(ctx, bucket, backupName)
+	return args.Error(0)
+}
+
+func (s *BackupService) GetBackup(bucket, name string) (*v1.Backup, error) {
+	args := s.Called(bucket, name)
+	var backup *v1.Backup
+	if args.Get(0) != nil {
+		backup = args.Get(0).(*v1.Backup)
+	}
+	return backup, args.Error(1)
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2018 the Heptio Ark contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/heptio/ark/pkg/apis/ark/v1"
+)
+
+// TestRestore builds a v1.Restore for use in tests.
+type TestRestore struct {
+	Restore *v1.Restore
+}
+
+// NewTestRestore returns a TestRestore with the given namespace, name, and phase.
+func NewTestRestore(ns, name string, phase v1.RestorePhase) *TestRestore {
+	return &TestRestore{
+		Restore: &v1.Restore{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: ns,
+				Name:      name,
+			},
+			Status: v1.RestoreStatus{
+				Phase: phase,
+			},
+		},
+	}
+}
+
+// WithBackup sets the name of the backup that this restore was created from.
+func (r *TestRestore) WithBackup(name string) *TestRestore {
+	r.Restore.Spec.BackupName = name
+	return r
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2017 the Heptio Ark contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/heptio/ark/pkg/cloudprovider"
+)
+
+// FakeSnapshotService is a fake implementation of cloudprovider.SnapshotService, useful for tests.
+// SnapshotsTaken records the IDs of snapshots that currently exist; DeleteSnapshot removes from
+// this set, and CreateSnapshot adds to it.
+type FakeSnapshotService struct {
+	SnapshotsTaken sets.String
+	SnapshotID     string
+}
+
+func (s *FakeSnapshotService) CreateSnapshot(volumeID, volumeAZ string, tags map[string]string) (string, error) {
+	s.SnapshotsTaken.Insert(s.SnapshotID)
+	return s.SnapshotID, nil
+}
+
+func (s *FakeSnapshotService) DeleteSnapshot(ctx context.Context, snapshotID string) error {
+	if !s.SnapshotsTaken.Has(snapshotID) {
+		return cloudprovider.ErrSnapshotNotFound
+	}
+	s.SnapshotsTaken.Delete(snapshotID)
+	return nil
+}
+
+func (s *FakeSnapshotService) GetVolumeID(pv *unstructured.Unstructured) (string, error) {
+	return "", nil
+}
+
+func (s *FakeSnapshotService) SetVolumeID(pv *unstructured.Unstructured, volumeID string) (*unstructured.Unstructured, error) {
+	return pv, nil
+}